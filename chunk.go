@@ -0,0 +1,164 @@
+package cookie
+
+import (
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// chunkMarker prefixes the value of the header cookie of a chunked value. It
+// is not part of the base64url alphabet, so signed and encrypted values never
+// start with it.
+const chunkMarker = "!"
+
+// DefaultChunkSize is a chunk size leaving room for the name and attributes of
+// a cookie within the 4096 bytes browsers accept.
+const DefaultChunkSize = 3800
+
+// WithChunking makes the Manager split values longer than chunkSize across
+// several cookies named name.0, name.1 and so on, up to maxChunks. The cookie
+// named name then holds a header recording the number of chunks and a
+// checksum of the value, and Get, GetSigned and GetEncrypted put the chunks
+// back together transparently.
+//
+// Since Remove cannot know how many chunks a previous value used, it removes
+// every chunk up to maxChunks. Uncompressed values starting with the marker
+// '!' are stored as a single chunk, so they are read back unchanged.
+func WithChunking(chunkSize, maxChunks int) Option {
+	if chunkSize < 1 || maxChunks < 1 {
+		panic("cookie: chunk size and max chunks must be positive")
+	}
+	return func(m *Manager) {
+		m.chunkSize = chunkSize
+		m.maxChunks = maxChunks
+	}
+}
+
+// chunkName returns the name of the cookie holding the i-th chunk of the value
+// of the named cookie.
+func chunkName(name string, i int) string {
+	return name + "." + strconv.Itoa(i)
+}
+
+// isChunkName reports whether chunking is enabled and cookieName is the name
+// of a chunk of the value of the named cookie.
+func (m *Manager) isChunkName(cookieName, name string) bool {
+	if m.maxChunks == 0 {
+		return false
+	}
+	index, ok := strings.CutPrefix(cookieName, name+".")
+	if !ok {
+		return false
+	}
+	i, err := strconv.Atoi(index)
+	return err == nil && i >= 0 && i < m.maxChunks
+}
+
+// chunkChecksum returns the checksum of a chunked value recorded in its
+// header cookie.
+func chunkChecksum(value string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(value)))
+}
+
+// parseChunkHeader parses the value of a header cookie, returning the number
+// of chunks and the checksum of the value.
+func parseChunkHeader(value string) (int, string, bool) {
+	header, ok := strings.CutPrefix(value, chunkMarker)
+	if !ok {
+		return 0, "", false
+	}
+	countStr, checksum, ok := strings.Cut(header, ".")
+	if !ok || len(checksum) != 8 {
+		return 0, "", false
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 1 {
+		return 0, "", false
+	}
+	return count, checksum, true
+}
+
+// writeCookie writes a cookie to the response, split into chunks if chunking
+// is enabled and its value is too long.
+func (m *Manager) writeCookie(w http.ResponseWriter, cookie *http.Cookie, priority Priority) error {
+	value := cookie.Value
+	if m.maxChunks == 0 || len(value) <= m.chunkSize && !strings.HasPrefix(value, chunkMarker) {
+		setCookie(w, cookie, priority)
+		return nil
+	}
+
+	count := (len(value) + m.chunkSize - 1) / m.chunkSize
+	if count > m.maxChunks {
+		return ErrTooManyChunks
+	}
+
+	header := *cookie
+	header.Value = chunkMarker + strconv.Itoa(count) + "." + chunkChecksum(value)
+	setCookie(w, &header, priority)
+
+	for i := 0; i < count; i++ {
+		chunk := *cookie
+		chunk.Name = chunkName(cookie.Name, i)
+		chunk.Value = value[i*m.chunkSize : min((i+1)*m.chunkSize, len(value))]
+		setCookie(w, &chunk, priority)
+	}
+	return nil
+}
+
+// readCookie returns the value of the named cookie of the request, putting it
+// back together if it was split into chunks.
+func (m *Manager) readCookie(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return m.joinChunks(name, cookie.Value, func(name string) (string, bool) {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return "", false
+		}
+		return c.Value, true
+	})
+}
+
+// joinChunks returns value, or if chunking is enabled and value is a chunk
+// header, the value put back together from the chunks returned by get.
+func (m *Manager) joinChunks(name, value string, get func(name string) (string, bool)) (string, error) {
+	if m.maxChunks == 0 {
+		return value, nil
+	}
+	count, checksum, ok := parseChunkHeader(value)
+	if !ok {
+		return value, nil
+	}
+	if count > m.maxChunks {
+		return "", ErrInvalidChunkedCookie
+	}
+
+	var b strings.Builder
+	for i := 0; i < count; i++ {
+		chunk, ok := get(chunkName(name, i))
+		if !ok {
+			return "", ErrInvalidChunkedCookie
+		}
+		b.WriteString(chunk)
+	}
+
+	joined := b.String()
+	if chunkChecksum(joined) != checksum {
+		return "", ErrInvalidChunkedCookie
+	}
+	return joined, nil
+}
+
+// removeChunks removes every chunk the value of a cookie may have been split
+// into, given the cookie removing it, if chunking is enabled.
+func (m *Manager) removeChunks(w http.ResponseWriter, cookie *http.Cookie, priority Priority) {
+	for i := 0; i < m.maxChunks; i++ {
+		chunk := *cookie
+		chunk.Name = chunkName(cookie.Name, i)
+		setCookie(w, &chunk, priority)
+	}
+}
@@ -0,0 +1,185 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var chunkedManager = NewManager(
+	WithSigningKey([]byte("super-secret-key")),
+	WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")),
+	WithChunking(100, 4),
+)
+
+func TestManager_Chunking(t *testing.T) {
+	large := strings.Repeat("0123456789", 25)
+
+	tests := map[string]struct {
+		value   string
+		opts    Options
+		cookies int
+	}{
+		"small":           {"small", Options{}, 1},
+		"exact":           {large[:100], Options{}, 1},
+		"large":           {large, Options{}, 4},
+		"marker":          {"!small", Options{}, 2},
+		"large signed":    {large, Options{Signed: true}, 5},
+		"large encrypted": {large, Options{Encrypted: true}, 5},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := chunkedManager.Set(w, "cookie", tt.value, tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cookies := w.Result().Cookies()
+			if len(cookies) != tt.cookies {
+				t.Fatalf("Expected %d cookies, but got %d", tt.cookies, len(cookies))
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, cookie := range cookies {
+				if len(cookie.Value) > 100 {
+					t.Errorf("Expected cookie %s to be at most 100 bytes, but got %d", cookie.Name, len(cookie.Value))
+				}
+				req.AddCookie(cookie)
+			}
+
+			var value string
+			var err error
+			switch {
+			case tt.opts.Encrypted:
+				value, err = chunkedManager.GetEncrypted(req, "cookie")
+			case tt.opts.Signed:
+				value, err = chunkedManager.GetSigned(req, "cookie")
+			default:
+				value, err = chunkedManager.Get(req, "cookie")
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != tt.value {
+				t.Errorf("Expected value '%s', but got '%s'", tt.value, value)
+			}
+		})
+	}
+}
+
+func TestManager_Chunking_TooManyChunks(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := chunkedManager.Set(w, "cookie", strings.Repeat("a", 401))
+	if err != ErrTooManyChunks {
+		t.Errorf("Expected error '%v', but got '%v'", ErrTooManyChunks, err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected no cookies, but got %d", len(w.Result().Cookies()))
+	}
+}
+
+func TestManager_Chunking_Invalid(t *testing.T) {
+	value := strings.Repeat("a", 150)
+	w := httptest.NewRecorder()
+	if err := chunkedManager.Set(w, "cookie", value); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cookies := w.Result().Cookies()
+
+	tests := map[string]func([]*http.Cookie) []*http.Cookie{
+		"missing chunk": func(c []*http.Cookie) []*http.Cookie {
+			return c[:2]
+		},
+		"tampered chunk": func(c []*http.Cookie) []*http.Cookie {
+			return []*http.Cookie{c[0], c[1], {Name: "cookie.1", Value: strings.Repeat("b", 50)}}
+		},
+		"too many chunks": func(c []*http.Cookie) []*http.Cookie {
+			return []*http.Cookie{{Name: "cookie", Value: "!5.00000000"}}
+		},
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, cookie := range modify(cookies) {
+				req.AddCookie(cookie)
+			}
+
+			_, err := chunkedManager.Get(req, "cookie")
+			if err != ErrInvalidChunkedCookie {
+				t.Errorf("Expected error '%v', but got '%v'", ErrInvalidChunkedCookie, err)
+			}
+		})
+	}
+}
+
+func TestManager_Chunking_Disabled(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: "!1.00000000"})
+
+	value, err := unsignedManager.Get(req, "cookie")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "!1.00000000" {
+		t.Errorf("Expected value '!1.00000000', but got '%s'", value)
+	}
+}
+
+func TestManager_Chunking_Remove(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := chunkedManager.Remove(w, "cookie"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	expected := []string{"cookie", "cookie.0", "cookie.1", "cookie.2", "cookie.3"}
+	if len(cookies) != len(expected) {
+		t.Fatalf("Expected %d cookies, but got %d", len(expected), len(cookies))
+	}
+	for i, cookie := range cookies {
+		if cookie.Name != expected[i] || cookie.MaxAge != -1 {
+			t.Errorf("Expected cookie %s to be removed, but got %s with max age %d", expected[i], cookie.Name, cookie.MaxAge)
+		}
+	}
+}
+
+func TestManager_Chunking_Flashes(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	for _, message := range []string{"Saved!", "Published!"} {
+		if err := chunkedManager.AddFlash(w, req, FlashInfo, message); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) < 2 {
+		t.Fatalf("Expected flashes to be chunked, but got %d cookies", len(cookies))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	flashes, err := chunkedManager.Flashes(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(flashes) != 2 || flashes[0].Message != "Saved!" || flashes[1].Message != "Published!" {
+		t.Errorf("Unexpected flashes: %+v", flashes)
+	}
+}
+
+func TestWithChunking_Invalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic, but got none")
+		}
+	}()
+	WithChunking(0, 1)
+}
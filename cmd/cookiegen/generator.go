@@ -0,0 +1,518 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// scalar describes how a scalar type supported by cookiegen is parsed from and
+// formatted to a cookie value, matching the reflection path of the cookie
+// package. Each template takes the expression to operate on.
+type scalar struct {
+	// parse returns the parsed value and an error, or is empty for strings.
+	parse string
+	// convert converts the result of parse to the type itself.
+	convert string
+	// format returns the formatted value.
+	format string
+	// nonzero reports whether the value is not the zero value.
+	nonzero string
+}
+
+var scalars = map[string]scalar{
+	"string":    {format: "%s", nonzero: `%s != ""`},
+	"bool":      {parse: "strconv.ParseBool(%s)", convert: "%s", format: "strconv.FormatBool(%s)", nonzero: "%s"},
+	"int":       intScalar("int", 0),
+	"int8":      intScalar("int8", 8),
+	"int16":     intScalar("int16", 16),
+	"int32":     intScalar("int32", 32),
+	"int64":     intScalar("int64", 64),
+	"uint":      uintScalar("uint", 0),
+	"uint8":     uintScalar("uint8", 8),
+	"uint16":    uintScalar("uint16", 16),
+	"uint32":    uintScalar("uint32", 32),
+	"uint64":    uintScalar("uint64", 64),
+	"float32":   {parse: "strconv.ParseFloat(%s, 32)", convert: "float32(%s)", format: "strconv.FormatFloat(float64(%s), 'f', -1, 32)", nonzero: "%s != 0"},
+	"float64":   {parse: "strconv.ParseFloat(%s, 64)", convert: "%s", format: "strconv.FormatFloat(%s, 'f', -1, 64)", nonzero: "%s != 0"},
+	"time.Time": {parse: "time.Parse(time.RFC3339, %s)", convert: "%s", format: "%s.Format(time.RFC3339)", nonzero: "%s != (time.Time{})"},
+}
+
+// cookiePackage is the import path of the cookie package.
+const cookiePackage = "github.com/syntaqx/cookie"
+
+// aliases maps predeclared aliases to the type they stand for.
+var aliases = map[string]string{
+	"byte": "uint8",
+	"rune": "int32",
+}
+
+func intScalar(typ string, bits int) scalar {
+	return scalar{
+		parse:   "strconv.ParseInt(%s, 10, " + strconv.Itoa(bits) + ")",
+		convert: typ + "(%s)",
+		format:  "strconv.FormatInt(int64(%s), 10)",
+		nonzero: "%s != 0",
+	}
+}
+
+func uintScalar(typ string, bits int) scalar {
+	return scalar{
+		parse:   "strconv.ParseUint(%s, 10, " + strconv.Itoa(bits) + ")",
+		convert: typ + "(%s)",
+		format:  "strconv.FormatUint(uint64(%s), 10)",
+		nonzero: "%s != 0",
+	}
+}
+
+// fieldShape is the shape of a field type supported by cookiegen.
+type fieldShape int
+
+const (
+	shapeScalar fieldShape = iota
+	shapePointer
+	shapeSlice
+)
+
+// field is a tagged struct field to generate code for.
+type field struct {
+	path  string // path from the outermost struct, such as "Prefs.Theme"
+	opts  tagOptions
+	shape fieldShape
+	typ   string // the scalar type, or the element type of pointers and slices
+}
+
+// tagOptions represents the options of a `cookie` struct tag supported by
+// cookiegen. It mirrors the parsing done by the cookie package.
+type tagOptions struct {
+	name       string
+	signed     bool
+	unsigned   bool
+	encrypted  bool
+	omitempty  bool
+	inline     bool
+	json       bool
+	sep        string
+	def        string
+	hasDefault bool
+	fallback   bool
+	rules      []string
+}
+
+func parseTag(tag string) tagOptions {
+	parts := strings.Split(tag, ",")
+	opts := tagOptions{name: parts[0]}
+
+	for i, part := range parts[1:] {
+		if def, ok := strings.CutPrefix(part, "default="); ok {
+			opts.def = strings.Join(append([]string{def}, parts[i+2:]...), ",")
+			opts.hasDefault = true
+			break
+		}
+
+		switch part {
+		case "signed":
+			opts.signed = true
+		case "unsigned":
+			opts.unsigned = true
+		case "encrypted":
+			opts.encrypted = true
+		case "omitempty":
+			opts.omitempty = true
+		case "inline":
+			opts.inline = true
+		case "fallback":
+			opts.fallback = true
+		case "json":
+			opts.json = true
+		default:
+			if sep, ok := strings.CutPrefix(part, "sep="); ok {
+				opts.sep = sep
+			} else if part != "" {
+				opts.rules = append(opts.rules, part)
+			}
+		}
+	}
+	if opts.sep == "" {
+		opts.sep = ","
+	}
+	return opts
+}
+
+// generator generates cookie binding methods for the struct types of a
+// package.
+type generator struct {
+	pkg     string
+	structs map[string]*ast.StructType
+}
+
+// newGenerator creates a generator for the given files of a package.
+func newGenerator(files []*ast.File) (*generator, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no Go files found")
+	}
+
+	g := &generator{
+		pkg:     files[0].Name.Name,
+		structs: make(map[string]*ast.StructType),
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if st, ok := typeSpec.Type.(*ast.StructType); ok && typeSpec.TypeParams == nil {
+					g.structs[typeSpec.Name.Name] = st
+				}
+			}
+		}
+	}
+	return g, nil
+}
+
+// generate returns the formatted source of the methods for the given types.
+func (g *generator) generate(types []string) ([]byte, error) {
+	var body bytes.Buffer
+	for _, name := range types {
+		st, ok := g.structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+		fields, err := g.fields(st, "", tagOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		writePopulate(&body, name, fields)
+		writeWrite(&body, name, fields)
+	}
+
+	imports := []string{"net/http"}
+	for _, pkg := range []string{"strconv", "strings", "time"} {
+		if bytes.Contains(body.Bytes(), []byte(pkg+".")) {
+			imports = append(imports, pkg)
+		}
+	}
+	sort.Strings(imports)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by cookiegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+	fmt.Fprintf(&buf, "import (\n")
+	for _, pkg := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", pkg)
+	}
+	fmt.Fprintf(&buf, "\n\t%q\n)\n", cookiePackage)
+	buf.Write(body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+// fields returns the tagged fields of a struct type, flattening embedded
+// structs and inline fields the way the cookie package does.
+func (g *generator) fields(st *ast.StructType, parentPath string, parent tagOptions) ([]field, error) {
+	var fields []field
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+			tag = reflect.StructTag(unquoted).Get("cookie")
+		}
+
+		names := make([]string, len(f.Names))
+		for i, name := range f.Names {
+			names[i] = name.Name
+		}
+		if len(names) == 0 {
+			// An embedded field is named after its type.
+			names = []string{embeddedName(f.Type)}
+		}
+
+		for _, name := range names {
+			path := name
+			if parentPath != "" {
+				path = parentPath + "." + name
+			}
+
+			if tag == "" {
+				if len(f.Names) == 0 {
+					nested, err := g.embedded(f.Type, path, parent)
+					if err != nil {
+						return nil, err
+					}
+					fields = append(fields, nested...)
+				}
+				continue
+			}
+
+			if !ast.IsExported(name) {
+				return nil, fmt.Errorf("field %s: unexported fields are not supported", path)
+			}
+
+			opts := parseTag(tag)
+			opts.name = parent.name + opts.name
+			opts.signed = opts.signed || parent.signed
+			opts.unsigned = opts.unsigned || (parent.unsigned && !opts.signed)
+			opts.encrypted = opts.encrypted || parent.encrypted
+			opts.omitempty = opts.omitempty || parent.omitempty
+
+			if opts.inline {
+				ident, ok := f.Type.(*ast.Ident)
+				if !ok || g.structs[ident.Name] == nil {
+					return nil, fmt.Errorf("field %s: inline fields must be structs declared in package %s", path, g.pkg)
+				}
+				nested, err := g.fields(g.structs[ident.Name], path, opts)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, nested...)
+				continue
+			}
+
+			if len(opts.rules) > 0 {
+				return nil, fmt.Errorf("field %s: validation rules are not supported", path)
+			}
+			if opts.fallback {
+				return nil, fmt.Errorf("field %s: the fallback option is not supported", path)
+			}
+			if opts.json {
+				return nil, fmt.Errorf("field %s: the json option is not supported", path)
+			}
+
+			shape, typ, err := fieldType(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", path, err)
+			}
+			fields = append(fields, field{path: path, opts: opts, shape: shape, typ: typ})
+		}
+	}
+	return fields, nil
+}
+
+// embedded returns the fields of an untagged embedded field, which are
+// flattened if it is a struct declared in the package.
+func (g *generator) embedded(expr ast.Expr, path string, parent tagOptions) ([]field, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if st, ok := g.structs[t.Name]; ok {
+			return g.fields(st, path, parent)
+		}
+		return nil, nil
+	case *ast.StarExpr:
+		// Embedded pointers are not walked by the cookie package.
+		return nil, nil
+	}
+	return nil, fmt.Errorf("embedded field %s: only structs declared in package %s are supported", path, g.pkg)
+}
+
+// embeddedName returns the name of an embedded field of the given type.
+func embeddedName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(t.X)
+	}
+	return ""
+}
+
+// fieldType returns the shape of a field type and its scalar type.
+func fieldType(expr ast.Expr) (fieldShape, string, error) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		if typ, ok := scalarType(t.X); ok {
+			return shapePointer, typ, nil
+		}
+	case *ast.ArrayType:
+		if t.Len != nil {
+			break
+		}
+		if typ, ok := scalarType(t.Elt); ok {
+			return shapeSlice, typ, nil
+		}
+	default:
+		if typ, ok := scalarType(expr); ok {
+			return shapeScalar, typ, nil
+		}
+	}
+	return 0, "", fmt.Errorf("unsupported type %s", exprString(expr))
+}
+
+// scalarType returns the name of a scalar type supported by cookiegen.
+func scalarType(expr ast.Expr) (string, bool) {
+	name := exprString(expr)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	_, ok := scalars[name]
+	return name, ok
+}
+
+// exprString returns the source of a type expression.
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	return buf.String()
+}
+
+// getter returns the Manager method used to read the cookie of a field.
+func getter(opts tagOptions) string {
+	switch {
+	case opts.encrypted:
+		return "GetEncrypted"
+	case opts.signed && !opts.unsigned:
+		return "GetSigned"
+	}
+	return "Get"
+}
+
+// writePopulate writes the PopulateFromCookies method of a struct type.
+func writePopulate(buf *bytes.Buffer, name string, fields []field) {
+	fmt.Fprintf(buf, "\n// PopulateFromCookies populates s with cookie values. It behaves like\n")
+	fmt.Fprintf(buf, "// Manager.PopulateFromCookies, without using reflection.\n")
+	fmt.Fprintf(buf, "func (s *%s) PopulateFromCookies(m *cookie.Manager, r *http.Request) error {\n", name)
+	fmt.Fprintf(buf, "\tif s == nil {\n\t\treturn cookie.ErrNonNilPointerRequired\n\t}\n\n")
+	fmt.Fprintf(buf, "\tvar errs cookie.FieldErrors\n")
+
+	for _, f := range fields {
+		fieldError := fmt.Sprintf("errs = append(errs, &cookie.FieldError{Field: %q, Cookie: %q, Value: value, Err: err})", f.path, f.opts.name)
+
+		fmt.Fprintf(buf, "\n\t// %s\n\t{\n", f.path)
+		fmt.Fprintf(buf, "\t\tvalue, err := m.%s(r, %q)\n", getter(f.opts), f.opts.name)
+		if f.opts.hasDefault {
+			fmt.Fprintf(buf, "\t\tif err == http.ErrNoCookie {\n\t\t\tvalue, err = %q, nil\n\t\t}\n", f.opts.def)
+		}
+
+		fmt.Fprintf(buf, "\t\tif err != nil {\n")
+		indent := "\t\t\t"
+		if f.opts.omitempty || f.shape == shapePointer {
+			fmt.Fprintf(buf, "\t\t\tif err != http.ErrNoCookie {\n")
+			indent = "\t\t\t\t"
+		}
+		fmt.Fprintf(buf, "%sif c, cookieErr := r.Cookie(%q); cookieErr == nil {\n%s\tvalue = c.Value\n%s}\n", indent, f.opts.name, indent, indent)
+		fmt.Fprintf(buf, "%s%s\n", indent, fieldError)
+		if f.opts.omitempty || f.shape == shapePointer {
+			fmt.Fprintf(buf, "\t\t\t}\n")
+		}
+		fmt.Fprintf(buf, "\t\t} else {\n")
+		writeParse(buf, f, fieldError)
+		fmt.Fprintf(buf, "\t\t}\n\t}\n")
+	}
+
+	fmt.Fprintf(buf, "\n\tif len(errs) > 0 {\n\t\treturn errs\n\t}\n\treturn nil\n}\n")
+}
+
+// writeParse writes the statements setting a field from value, appending to
+// errs on failure.
+func writeParse(buf *bytes.Buffer, f field, fieldError string) {
+	s := scalars[f.typ]
+	dst := "s." + f.path
+
+	switch f.shape {
+	case shapeScalar:
+		if s.parse == "" {
+			fmt.Fprintf(buf, "\t\t\t%s = value\n", dst)
+			return
+		}
+		fmt.Fprintf(buf, "\t\t\tif v, err := %s; err != nil {\n\t\t\t\t%s\n\t\t\t} else {\n\t\t\t\t%s = %s\n\t\t\t}\n",
+			fmt.Sprintf(s.parse, "value"), fieldError, dst, fmt.Sprintf(s.convert, "v"))
+	case shapePointer:
+		if s.parse == "" {
+			fmt.Fprintf(buf, "\t\t\tv := value\n\t\t\t%s = &v\n", dst)
+			return
+		}
+		fmt.Fprintf(buf, "\t\t\tif v, err := %s; err != nil {\n\t\t\t\t%s\n\t\t\t} else {\n\t\t\t\tp := %s\n\t\t\t\t%s = &p\n\t\t\t}\n",
+			fmt.Sprintf(s.parse, "value"), fieldError, fmt.Sprintf(s.convert, "v"), dst)
+	case shapeSlice:
+		// An empty value is an empty slice.
+		fmt.Fprintf(buf, "\t\t\tif value == \"\" {\n\t\t\t\t%s = []%s{}\n\t\t\t} else {\n", dst, f.typ)
+		defer fmt.Fprintf(buf, "\t\t\t}\n")
+		if s.parse == "" {
+			fmt.Fprintf(buf, "\t\t\t%s = strings.Split(value, %q)\n", dst, f.opts.sep)
+			return
+		}
+		parse, convert := s.parse, s.convert
+		if f.typ == "int" {
+			// []int is parsed with strconv.Atoi by the cookie package.
+			parse, convert = "strconv.Atoi(%s)", "%s"
+		}
+		fmt.Fprintf(buf, "\t\t\tparts := strings.Split(value, %q)\n", f.opts.sep)
+		fmt.Fprintf(buf, "\t\t\tslice := make([]%s, len(parts))\n", f.typ)
+		fmt.Fprintf(buf, "\t\t\tvar err error\n")
+		fmt.Fprintf(buf, "\t\t\tfor i, part := range parts {\n")
+		fmt.Fprintf(buf, "\t\t\t\tv, parseErr := %s\n", fmt.Sprintf(parse, "part"))
+		fmt.Fprintf(buf, "\t\t\t\tif parseErr != nil {\n\t\t\t\t\terr = parseErr\n\t\t\t\t\tbreak\n\t\t\t\t}\n")
+		fmt.Fprintf(buf, "\t\t\t\tslice[i] = %s\n\t\t\t}\n", fmt.Sprintf(convert, "v"))
+		fmt.Fprintf(buf, "\t\t\tif err != nil {\n\t\t\t\t%s\n\t\t\t} else {\n\t\t\t\t%s = slice\n\t\t\t}\n", fieldError, dst)
+	}
+}
+
+// writeWrite writes the WriteToCookies method of a struct type.
+func writeWrite(buf *bytes.Buffer, name string, fields []field) {
+	fmt.Fprintf(buf, "\n// WriteToCookies writes the tagged fields of s as cookies. It behaves like\n")
+	fmt.Fprintf(buf, "// Manager.WriteToCookies, without using reflection.\n")
+	fmt.Fprintf(buf, "func (s *%s) WriteToCookies(m *cookie.Manager, w http.ResponseWriter, opts ...cookie.Options) error {\n", name)
+	fmt.Fprintf(buf, "\tif s == nil {\n\t\treturn cookie.ErrNonNilPointerRequired\n\t}\n\n")
+	fmt.Fprintf(buf, "\tvar o cookie.Options\n\tif len(opts) > 0 {\n\t\to = opts[0]\n\t}\n")
+
+	for _, f := range fields {
+		s := scalars[f.typ]
+		src := "s." + f.path
+
+		// Zero values are skipped with omitempty, and nil pointers always.
+		var cond string
+		switch {
+		case f.shape == shapeScalar && f.opts.omitempty:
+			cond = fmt.Sprintf(s.nonzero, src)
+		case f.shape == shapePointer || f.shape == shapeSlice && f.opts.omitempty:
+			cond = src + " != nil"
+		}
+
+		fmt.Fprintf(buf, "\n\t// %s\n", f.path)
+		if cond != "" {
+			fmt.Fprintf(buf, "\tif %s {\n", cond)
+		} else {
+			fmt.Fprintf(buf, "\t{\n")
+		}
+
+		switch f.shape {
+		case shapeScalar:
+			fmt.Fprintf(buf, "\t\tvalue := %s\n", fmt.Sprintf(s.format, src))
+		case shapePointer:
+			fmt.Fprintf(buf, "\t\tvalue := %s\n", fmt.Sprintf(s.format, "(*"+src+")"))
+		case shapeSlice:
+			// Elements that could not be told apart when reading the value
+			// back are rejected.
+			fmt.Fprintf(buf, "\t\tparts := make([]string, len(%s))\n", src)
+			fmt.Fprintf(buf, "\t\tfor i, v := range %s {\n\t\t\tparts[i] = %s\n", src, fmt.Sprintf(s.format, "v"))
+			fmt.Fprintf(buf, "\t\t\tif strings.Contains(parts[i], %q) {\n\t\t\t\treturn cookie.ErrAmbiguousValue\n\t\t\t}\n\t\t}\n", f.opts.sep)
+			fmt.Fprintf(buf, "\t\tif len(parts) == 1 && parts[0] == \"\" {\n\t\t\treturn cookie.ErrAmbiguousValue\n\t\t}\n")
+			fmt.Fprintf(buf, "\t\tvalue := strings.Join(parts, %q)\n", f.opts.sep)
+		}
+
+		fmt.Fprintf(buf, "\n\t\tfieldOpts := o\n")
+		fmt.Fprintf(buf, "\t\tfieldOpts.Encrypted = %t\n", f.opts.encrypted)
+		fmt.Fprintf(buf, "\t\tfieldOpts.Signed = %t\n", f.opts.signed && !f.opts.unsigned)
+		fmt.Fprintf(buf, "\t\tif err := m.Set(w, %q, value, fieldOpts); err != nil {\n\t\t\treturn err\n\t\t}\n\t}\n", f.opts.name)
+	}
+
+	fmt.Fprintf(buf, "\treturn nil\n}\n")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "cookiegentest")
+	output := filepath.Join(dir, "cookies_cookiegen.go")
+
+	files, err := parseDir(dir, output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g, err := newGenerator(files)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	src, err := g.generate([]string{"Cookies"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(src, expected) {
+		t.Errorf("Expected %s to be up to date, run go generate", output)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected string
+	}{
+		"missing type": {
+			src:      "type Other struct{}",
+			expected: "struct type Cookies not found",
+		},
+		"unsupported type": {
+			src:      "type Cookies struct { Field complex128 `cookie:\"field\"` }",
+			expected: "Cookies: field Field: unsupported type complex128",
+		},
+		"map": {
+			src:      "type Cookies struct { Field map[string]string `cookie:\"field\"` }",
+			expected: "Cookies: field Field: unsupported type map[string]string",
+		},
+		"named type": {
+			src:      "type Theme string\ntype Cookies struct { Field Theme `cookie:\"field\"` }",
+			expected: "Cookies: field Field: unsupported type Theme",
+		},
+		"validation rule": {
+			src:      "type Cookies struct { Field int `cookie:\"field,min=1\"` }",
+			expected: "Cookies: field Field: validation rules are not supported",
+		},
+		"fallback": {
+			src:      "type Cookies struct { Field int `cookie:\"field,signed,fallback,default=1\"` }",
+			expected: "Cookies: field Field: the fallback option is not supported",
+		},
+		"json": {
+			src:      "type Cookies struct { Field []string `cookie:\"field,json\"` }",
+			expected: "Cookies: field Field: the json option is not supported",
+		},
+		"unexported": {
+			src:      "type Cookies struct { field int `cookie:\"field\"` }",
+			expected: "Cookies: field field: unexported fields are not supported",
+		},
+		"inline": {
+			src:      "type Cookies struct { Field string `cookie:\"prefix_,inline\"` }",
+			expected: "Cookies: field Field: inline fields must be structs declared in package example",
+		},
+		"embedded": {
+			src:      "import \"net/http\"\ntype Cookies struct { http.Cookie }",
+			expected: "Cookies: embedded field Cookie: only structs declared in package example are supported",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "example.go", "package example\n"+tt.src, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			g, err := newGenerator([]*ast.File{file})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			_, err = g.generate([]string{"Cookies"})
+			if err == nil {
+				t.Fatal("Expected error, but got nil")
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestGenerate_SkipsUntaggedFields(t *testing.T) {
+	src := "package example\n" +
+		"type Base struct { ID int `cookie:\"id\"` }\n" +
+		"type Cookies struct {\n" +
+		"\t*Base\n" +
+		"\tName string\n" +
+		"\tTheme string `cookie:\"theme\"`\n" +
+		"}\n"
+
+	file, err := parser.ParseFile(token.NewFileSet(), "example.go", src, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g, err := newGenerator([]*ast.File{file})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out, err := g.generate([]string{"Cookies"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(out), `"id"`) || strings.Contains(string(out), "s.Name") {
+		t.Errorf("Expected untagged fields to be skipped, but got:\n%s", out)
+	}
+	if !strings.Contains(string(out), `m.Get(r, "theme")`) {
+		t.Errorf("Expected theme to be populated, but got:\n%s", out)
+	}
+}
+
+func TestNewGenerator_NoFiles(t *testing.T) {
+	if _, err := newGenerator(nil); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	opts := parseTag("cookie,signed,sep=|,default=a|b,c")
+	if opts.name != "cookie" || !opts.signed || opts.sep != "|" || opts.def != "a|b,c" || !opts.hasDefault {
+		t.Errorf("Unexpected result: %+v", opts)
+	}
+
+	if opts := parseTag("cookie"); opts.sep != "," {
+		t.Errorf("Expected default separator ',', but got '%s'", opts.sep)
+	}
+}
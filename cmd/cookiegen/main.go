@@ -0,0 +1,93 @@
+// Command cookiegen generates reflection-free PopulateFromCookies and
+// WriteToCookies methods for structs with `cookie` tags.
+//
+// It is meant to be run by go generate, from the package declaring the
+// structs:
+//
+//	//go:generate go run github.com/syntaqx/cookie/cmd/cookiegen -type=RequestCookies
+//
+// The generated methods take the Manager to use, and behave like
+// Manager.PopulateFromCookies and Manager.WriteToCookies for the types they
+// support: strings, bools, integers, floats and time.Time, pointers and
+// slices of those, embedded structs and inline structs declared in the same
+// package. Custom type handlers are not consulted. Fields of other types, and
+// tags with validation rules or the fallback or json options, are reported as
+// errors, in which case the reflection path should be used instead.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <first type>_cookiegen.go")
+	dir := flag.String("dir", ".", "directory of the package declaring the types")
+	flag.Parse()
+
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	types := strings.Split(*typeNames, ",")
+
+	if *output == "" {
+		*output = strings.ToLower(types[0]) + "_cookiegen.go"
+	}
+	outputPath := filepath.Join(*dir, *output)
+
+	if err := run(*dir, outputPath, types); err != nil {
+		fmt.Fprintln(os.Stderr, "cookiegen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the methods for the given types of the package in dir, and
+// writes them to outputPath.
+func run(dir, outputPath string, types []string) error {
+	files, err := parseDir(dir, outputPath)
+	if err != nil {
+		return err
+	}
+
+	g, err := newGenerator(files)
+	if err != nil {
+		return err
+	}
+
+	src, err := g.generate(types)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputPath, src, 0o644)
+}
+
+// parseDir parses the non-test Go files of the package in dir, skipping the
+// output file of a previous run.
+func parseDir(dir, outputPath string) ([]*ast.File, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") || filepath.Clean(path) == filepath.Clean(outputPath) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
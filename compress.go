@@ -0,0 +1,93 @@
+package cookie
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"io"
+	"strings"
+)
+
+// compressedMarker prefixes compressed cookie values. It is not part of the
+// base64url alphabet, so signed and encrypted values never start with it.
+const compressedMarker = "~"
+
+// maxDecompressedSize limits the size of decompressed cookie values, guarding
+// against values crafted to expand without bound.
+const maxDecompressedSize = 1 << 20
+
+// WithCompression makes the Manager compress cookie values with DEFLATE before
+// they are signed or encrypted, whenever that makes them smaller. Compressed
+// values are prefixed with a marker, so Get, GetSigned and GetEncrypted can
+// tell them apart from uncompressed values and decompress them transparently.
+// GetSigned and GetEncrypted only decompress values once verified or
+// decrypted.
+//
+// Values are only decompressed by a Manager with compression enabled, and
+// uncompressed values starting with the marker '~' are stored compressed even
+// when that makes them larger, so they are read back unchanged.
+func WithCompression() Option {
+	return func(m *Manager) {
+		m.compression = true
+	}
+}
+
+// compress compresses value if compression is enabled and the result is
+// smaller, or if value starts with the marker.
+func (m *Manager) compress(value string) (string, error) {
+	if !m.compression {
+		return value, nil
+	}
+
+	compressed, err := compressValue(value)
+	if err != nil {
+		return "", err
+	}
+	if len(compressed) < len(value) || strings.HasPrefix(value, compressedMarker) {
+		return compressed, nil
+	}
+	return value, nil
+}
+
+// decompress decompresses value if compression is enabled and it starts with
+// the marker.
+func (m *Manager) decompress(value string) (string, error) {
+	if !m.compression || !strings.HasPrefix(value, compressedMarker) {
+		return value, nil
+	}
+	return decompressValue(value)
+}
+
+// compressValue compresses value with DEFLATE, returning the marker followed
+// by the base64url encoding of the compressed data.
+func compressValue(value string) (string, error) {
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := fw.Write([]byte(value)); err != nil {
+		return "", err
+	}
+	if err := fw.Close(); err != nil {
+		return "", err
+	}
+	return compressedMarker + base64.URLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decompressValue decompresses a value compressed by compressValue.
+func decompressValue(value string) (string, error) {
+	data, err := base64.URLEncoding.DecodeString(strings.TrimPrefix(value, compressedMarker))
+	if err != nil {
+		return "", ErrInvalidCompressedCookie
+	}
+
+	fr := flate.NewReader(bytes.NewReader(data))
+	defer fr.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(fr, maxDecompressedSize+1))
+	if err != nil || len(decompressed) > maxDecompressedSize {
+		return "", ErrInvalidCompressedCookie
+	}
+	return string(decompressed), nil
+}
@@ -0,0 +1,187 @@
+package cookie
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var compressedManager = NewManager(
+	WithSigningKey([]byte("super-secret-key")),
+	WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")),
+	WithCompression(),
+)
+
+func TestManager_Compression(t *testing.T) {
+	large := strings.Repeat("compressible ", 100)
+
+	tests := map[string]struct {
+		value      string
+		opts       Options
+		compressed bool
+	}{
+		"large":            {large, Options{}, true},
+		"small":            {"small", Options{}, false},
+		"marker":           {"~small", Options{}, true},
+		"large signed":     {large, Options{Signed: true}, true},
+		"small signed":     {"small", Options{Signed: true}, false},
+		"large encrypted":  {large, Options{Encrypted: true}, true},
+		"marker encrypted": {"~small", Options{Encrypted: true}, true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := compressedManager.Set(w, "cookie", tt.value, tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			cookie := w.Result().Cookies()[0]
+
+			if tt.compressed && len(cookie.Value) >= len(tt.value) && !strings.HasPrefix(tt.value, compressedMarker) {
+				t.Errorf("Expected value to be compressed, but got %d bytes for %d", len(cookie.Value), len(tt.value))
+			}
+			if !tt.compressed && !tt.opts.Signed && cookie.Value != tt.value {
+				t.Errorf("Expected value '%s', but got '%s'", tt.value, cookie.Value)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(cookie)
+
+			var value string
+			var err error
+			switch {
+			case tt.opts.Encrypted:
+				value, err = compressedManager.GetEncrypted(req, "cookie")
+			case tt.opts.Signed:
+				value, err = compressedManager.GetSigned(req, "cookie")
+			default:
+				value, err = compressedManager.Get(req, "cookie")
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != tt.value {
+				t.Errorf("Expected value '%s', but got '%s'", tt.value, value)
+			}
+		})
+	}
+}
+
+func TestManager_Compression_Disabled(t *testing.T) {
+	compressed, err := compressValue(strings.Repeat("a", 100))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: compressed})
+
+	value, err := unsignedManager.Get(req, "cookie")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != compressed {
+		t.Errorf("Expected value '%s', but got '%s'", compressed, value)
+	}
+}
+
+func TestManager_Compression_SetEncoded(t *testing.T) {
+	value := strings.Repeat("x", 3000)
+
+	encoded, err := compressedManager.Encode("cookie", value, Options{Signed: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := compressedManager.SetEncoded(w, "cookie", encoded, Options{Signed: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Value != encoded {
+		t.Errorf("Expected value '%s', but got '%s'", encoded, cookie.Value)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+
+	got, err := compressedManager.GetSigned(req, "cookie")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != value {
+		t.Errorf("Expected value of %d bytes, but got %d bytes", len(value), len(got))
+	}
+}
+
+func TestManager_Compression_NotVerified(t *testing.T) {
+	// Signed and encrypted values are only decompressed once verified, so
+	// forged compressed values are rejected without being inflated.
+	value, err := compressValue(strings.Repeat("x", maxDecompressedSize))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: value})
+
+	if _, err := compressedManager.GetSigned(req, "cookie"); err != ErrInvalidSignedCookieFormat {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidSignedCookieFormat, err)
+	}
+	if _, err := compressedManager.GetEncrypted(req, "cookie"); err == nil || err == ErrInvalidCompressedCookie {
+		t.Errorf("Expected decryption error, but got '%v'", err)
+	}
+}
+
+func TestManager_Compression_Invalid(t *testing.T) {
+	var bomb bytes.Buffer
+	fw, _ := flate.NewWriter(&bomb, flate.BestCompression)
+	fw.Write(make([]byte, maxDecompressedSize+1))
+	fw.Close()
+
+	tests := map[string]string{
+		"base64": "~not base64!",
+		"flate":  "~" + base64.URLEncoding.EncodeToString([]byte("not flate")),
+		"bomb":   "~" + base64.URLEncoding.EncodeToString(bomb.Bytes()),
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "cookie", Value: value})
+
+			_, err := compressedManager.Get(req, "cookie")
+			if err != ErrInvalidCompressedCookie {
+				t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCompressedCookie, err)
+			}
+		})
+	}
+}
+
+func TestManager_Compression_Flashes(t *testing.T) {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	message := strings.Repeat("Saved! ", 200)
+	for i := 0; i < 2; i++ {
+		if err := compressedManager.AddFlash(w, req, FlashInfo, message); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	flashes, err := compressedManager.Flashes(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(flashes) != 2 || flashes[1].Message != message {
+		t.Errorf("Unexpected flashes: %d", len(flashes))
+	}
+}
@@ -1,10 +1,11 @@
 package cookie
 
 import (
-	"encoding/base64"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,12 +20,73 @@ type Options struct {
 	HttpOnly bool
 	SameSite http.SameSite
 	Signed   bool
+
+	// Encrypted seals the cookie value with AES-256-GCM, providing both
+	// confidentiality and integrity. When set, Signed is ignored.
+	Encrypted bool
+
+	// Partitioned stores the cookie in storage partitioned by top-level site
+	// (CHIPS), as needed by embedded third-party content. Partitioned cookies
+	// must be Secure, so setting it also sets Secure.
+	Partitioned bool
+
+	// Priority sets the Priority attribute, which Chrome uses to decide which
+	// cookies to evict first when a domain has too many.
+	Priority Priority
+}
+
+// Priority is the value of the Priority attribute of a cookie. It is not part
+// of any standard and is ignored by browsers other than Chrome.
+type Priority int
+
+// Cookie priorities.
+const (
+	// PriorityDefault omits the attribute, which Chrome treats as Medium.
+	PriorityDefault Priority = iota
+	// PriorityLow makes the cookie among the first evicted.
+	PriorityLow
+	// PriorityMedium makes the cookie evicted before High ones.
+	PriorityMedium
+	// PriorityHigh makes the cookie among the last evicted.
+	PriorityHigh
+)
+
+// String returns the value of the Priority attribute, or an empty string for
+// PriorityDefault and unknown values.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "Low"
+	case PriorityMedium:
+		return "Medium"
+	case PriorityHigh:
+		return "High"
+	}
+	return ""
 }
 
 // Manager handles cookie operations.
 type Manager struct {
-	signingKey     []byte
-	customHandlers map[reflect.Type]CustomTypeHandler
+	signingKey              []byte
+	signer                  Signer
+	keyring                 map[string]Signer
+	primaryKeyID            string
+	encryptionKey           []byte
+	compression             bool
+	prefixCorrection        bool
+	chunkSize               int
+	maxChunks               int
+	timestamped             bool
+	maxAge                  time.Duration
+	clock                   func() time.Time
+	boundSignatures         bool
+	signaturePurpose        string
+	acceptUnboundSignatures bool
+	customHandlers          map[reflect.Type]CustomTypeHandler
+	validators              map[string]Validator
+	fieldPlans              sync.Map // map[reflect.Type]*fieldPlan
+	flashCookieName         string
+	flashMaxSize            int
 }
 
 // Option is a function type for configuring the Manager.
@@ -37,6 +99,99 @@ func WithSigningKey(key []byte) Option {
 	}
 }
 
+// WithSigner sets the Signer used to sign cookies for the Manager, replacing
+// the HMAC-SHA256 signer derived from WithSigningKey.
+func WithSigner(signer Signer) Option {
+	return func(m *Manager) {
+		m.signer = signer
+	}
+}
+
+// WithKeyring sets a keyring of signing keys for the Manager, allowing keys to
+// be rotated without invalidating cookies signed with an older key. The key
+// identified by primary is used to sign new cookies, while every key in keys is
+// accepted when verifying. Signatures carry the ID of the key that produced
+// them, so key IDs must not contain '.' or '|'.
+//
+// Signatures without a key ID, such as those created before the keyring was
+// introduced, are verified with the key set by WithSigningKey or the Signer
+// set by WithSigner, and rejected when neither is set.
+func WithKeyring(primary string, keys map[string][]byte) Option {
+	signers := make(map[string]Signer, len(keys))
+	for id, key := range keys {
+		signers[id] = NewHMACSHA256Signer(key)
+	}
+	return WithSignerKeyring(primary, signers)
+}
+
+// WithSignerKeyring is like WithKeyring, but accepts a Signer for each key ID,
+// allowing algorithms to be mixed or migrated during rotation.
+func WithSignerKeyring(primary string, signers map[string]Signer) Option {
+	if _, ok := signers[primary]; !ok {
+		panic("cookie: primary key " + strconv.Quote(primary) + " not found in keyring")
+	}
+	for id := range signers {
+		if id == "" || strings.ContainsAny(id, ".|") {
+			panic("cookie: invalid key ID " + strconv.Quote(id))
+		}
+	}
+	return func(m *Manager) {
+		m.keyring = signers
+		m.primaryKeyID = primary
+	}
+}
+
+// WithSignatureMaxAge embeds an issued-at timestamp in every value signed by
+// the Manager, and makes GetSigned reject values signed more than maxAge ago
+// with ErrSignatureExpired. A maxAge of zero embeds timestamps without
+// enforcing a default max age, leaving it to each call to GetSigned.
+func WithSignatureMaxAge(maxAge time.Duration) Option {
+	return func(m *Manager) {
+		m.timestamped = true
+		m.maxAge = maxAge
+	}
+}
+
+// WithBoundSignatures binds signatures to the name of the cookie they were
+// created for, so a signed value cannot be copied into another cookie and
+// still pass verification. The purpose, which may be empty, is also signed,
+// allowing values signed for one context to be rejected in another.
+func WithBoundSignatures(purpose string) Option {
+	return func(m *Manager) {
+		m.boundSignatures = true
+		m.signaturePurpose = purpose
+	}
+}
+
+// WithUnboundSignaturesAccepted makes a Manager using WithBoundSignatures also
+// accept signatures that are not bound to a cookie name. This is intended for
+// migrating cookies signed before binding was enabled, and should be removed
+// once they have expired.
+func WithUnboundSignaturesAccepted() Option {
+	return func(m *Manager) {
+		m.acceptUnboundSignatures = true
+	}
+}
+
+// WithClock sets the function used by the Manager to get the current time.
+// It defaults to time.Now, and is mostly useful for testing.
+func WithClock(clock func() time.Time) Option {
+	return func(m *Manager) {
+		m.clock = clock
+	}
+}
+
+// WithEncryptionKey sets the key used to encrypt cookies for the Manager. The
+// key must be 32 bytes long, selecting AES-256.
+func WithEncryptionKey(key []byte) Option {
+	if len(key) != 32 {
+		panic("cookie: encryption key must be 32 bytes, got " + strconv.Itoa(len(key)))
+	}
+	return func(m *Manager) {
+		m.encryptionKey = key
+	}
+}
+
 // WithCustomHandler registers a custom type handler for the Manager.
 func WithCustomHandler(typ reflect.Type, handler CustomTypeHandler) Option {
 	return func(m *Manager) {
@@ -47,7 +202,11 @@ func WithCustomHandler(typ reflect.Type, handler CustomTypeHandler) Option {
 // NewManager creates a new Manager with the given options.
 func NewManager(opts ...Option) *Manager {
 	m := &Manager{
-		customHandlers: make(map[reflect.Type]CustomTypeHandler),
+		customHandlers:  make(map[reflect.Type]CustomTypeHandler),
+		validators:      make(map[string]Validator),
+		clock:           time.Now,
+		flashCookieName: DefaultFlashCookieName,
+		flashMaxSize:    DefaultFlashMaxSize,
 	}
 	for _, opt := range opts {
 		opt(m)
@@ -57,66 +216,138 @@ func NewManager(opts ...Option) *Manager {
 
 // Get retrieves an unsigned cooke value.
 func (m *Manager) Get(r *http.Request, name string) (string, error) {
-	cookie, err := r.Cookie(name)
+	value, err := m.readCookie(r, name)
 	if err != nil {
 		return "", err
 	}
-	return cookie.Value, nil
+	return m.decompress(value)
 }
 
-// GetSigned retrieves a signed cookie value.
-func (m *Manager) GetSigned(r *http.Request, name string) (string, error) {
-	value, err := m.Get(r, name)
+// GetSigned retrieves a signed cookie value. If a max age is given, it
+// overrides the one set by WithSignatureMaxAge, and the value is rejected with
+// ErrSignatureExpired when it was signed longer ago or carries no timestamp.
+func (m *Manager) GetSigned(r *http.Request, name string, maxAge ...time.Duration) (string, error) {
+	// The value is only decompressed once verified, so forged values are
+	// never inflated.
+	value, err := m.readCookie(r, name)
 	if err != nil {
 		return "", err
 	}
 
-	parts := strings.Split(value, "|")
-	if len(parts) != 2 {
-		return "", ErrInvalidSignedCookieFormat
+	age := m.maxAge
+	if len(maxAge) > 0 {
+		age = maxAge[0]
+	}
+	value, err = m.verifyValue(name, value, age)
+	if err != nil {
+		return "", err
 	}
+	return m.decompress(value)
+}
 
-	data, signature := parts[0], parts[1]
-	dataBytes, err := base64.URLEncoding.DecodeString(data)
+// GetEncrypted retrieves an encrypted cookie value.
+func (m *Manager) GetEncrypted(r *http.Request, name string) (string, error) {
+	if !m.CanEncrypt() {
+		return "", ErrEncryptionKeyRequired
+	}
+	value, err := m.readCookie(r, name)
+	if err != nil {
+		return "", err
+	}
+	value, err = decryptCookieValue(name, value, m.encryptionKey)
 	if err != nil {
 		return "", err
 	}
-	signatureBytes, err := base64.URLEncoding.DecodeString(signature)
+	return m.decompress(value)
+}
+
+// Encode returns the value Set would write for a cookie, compressed when
+// enabled with WithCompression, then signed or encrypted according to the
+// given options. This allows the size of a cookie to be checked before it is
+// written with SetEncoded. It returns ErrSigningKeyRequired or ErrEncryptionKeyRequired when
+// the Manager lacks the key the options call for.
+func (m *Manager) Encode(name, value string, opts ...Options) (string, error) {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	value, err := m.compress(value)
 	if err != nil {
 		return "", err
 	}
 
-	if verify([]byte(data), signatureBytes, m.signingKey) {
-		return string(dataBytes), nil
+	switch {
+	case o.Encrypted:
+		if !m.CanEncrypt() {
+			return "", ErrEncryptionKeyRequired
+		}
+		return encryptCookieValue(name, value, m.encryptionKey)
+	case o.Signed:
+		if !m.CanSign() {
+			return "", ErrSigningKeyRequired
+		}
+		return m.signValue(name, value)
 	}
-	return "", ErrInvalidCookieSignature
+	return value, nil
 }
 
-// Set sets the value of a cookie.
+// Set sets the value of a cookie. Cookies named with SecurePrefix or
+// HostPrefix must follow the rules of the prefix, see WithPrefixCorrection.
 func (m *Manager) Set(w http.ResponseWriter, name, value string, opts ...Options) error {
 	var o Options
 	if len(opts) > 0 {
 		o = opts[0]
 	}
+	o.Secure = o.Secure || o.Partitioned
 
-	if o.Signed && m.signingKey != nil {
-		value = signCookieValue(value, m.signingKey)
+	o, err := m.checkPrefix(name, o)
+	if err != nil {
+		return err
+	}
+
+	value, err = m.Encode(name, value, o)
+	if err != nil {
+		return err
 	}
+	return m.writeValue(w, name, value, o)
+}
 
+// SetEncoded sets the value of a cookie to one returned by Encode, writing it
+// as is. The Signed and Encrypted options are ignored, as the value was already
+// signed or encrypted by Encode. Like Set, it checks the rules of the
+// SecurePrefix and HostPrefix name prefixes.
+func (m *Manager) SetEncoded(w http.ResponseWriter, name, value string, opts ...Options) error {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o.Secure = o.Secure || o.Partitioned
+
+	o, err := m.checkPrefix(name, o)
+	if err != nil {
+		return err
+	}
+	return m.writeValue(w, name, value, o)
+}
+
+// writeValue writes a cookie with the encoded value and the given options,
+// split into chunks when enabled with WithChunking.
+func (m *Manager) writeValue(w http.ResponseWriter, name, value string, o Options) error {
 	cookie := &http.Cookie{
-		Name:     name,
-		Value:    value,
-		Path:     o.Path,
-		Domain:   o.Domain,
-		Expires:  o.Expires,
-		MaxAge:   o.MaxAge,
-		Secure:   o.Secure,
-		HttpOnly: o.HttpOnly,
-		SameSite: o.SameSite,
-	}
-
-	http.SetCookie(w, cookie)
-	return nil
+		Name:        name,
+		Value:       value,
+		Path:        o.Path,
+		Domain:      o.Domain,
+		Expires:     o.Expires,
+		MaxAge:      o.MaxAge,
+		Secure:      o.Secure,
+		HttpOnly:    o.HttpOnly,
+		SameSite:    o.SameSite,
+		Partitioned: o.Partitioned,
+	}
+
+	return m.writeCookie(w, cookie, o.Priority)
 }
 
 // SetSigned sets a signed value of a cookie.
@@ -129,23 +360,58 @@ func (m *Manager) SetSigned(w http.ResponseWriter, name, value string, opts ...O
 	return m.Set(w, name, value, o)
 }
 
-// Remove removes a cookie from the response.
+// SetEncrypted sets an encrypted value of a cookie.
+func (m *Manager) SetEncrypted(w http.ResponseWriter, name, value string, opts ...Options) error {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o.Encrypted = true
+	return m.Set(w, name, value, o)
+}
+
+// Remove removes a cookie from the response, along with any chunks its value
+// may have been split into when enabled with WithChunking. Like Set, it checks
+// the rules of the SecurePrefix and HostPrefix name prefixes.
 func (m *Manager) Remove(w http.ResponseWriter, name string, opts ...Options) error {
 	var o Options
 	if len(opts) > 0 {
 		o = opts[0]
 	}
+	o.Secure = o.Secure || o.Partitioned
+
+	o, err := m.checkPrefix(name, o)
+	if err != nil {
+		return err
+	}
+
 	cookie := &http.Cookie{
-		Name:     name,
-		Value:    "",
-		Path:     o.Path,
-		Domain:   o.Domain,
-		Expires:  time.Unix(0, 0),
-		MaxAge:   -1,
-		Secure:   o.Secure,
-		HttpOnly: o.HttpOnly,
-		SameSite: o.SameSite,
-	}
-	http.SetCookie(w, cookie)
+		Name:        name,
+		Value:       "",
+		Path:        o.Path,
+		Domain:      o.Domain,
+		Expires:     time.Unix(0, 0),
+		MaxAge:      -1,
+		Secure:      o.Secure,
+		HttpOnly:    o.HttpOnly,
+		SameSite:    o.SameSite,
+		Partitioned: o.Partitioned,
+	}
+	setCookie(w, cookie, o.Priority)
+	m.removeChunks(w, cookie, o.Priority)
 	return nil
 }
+
+// setCookie adds a Set-Cookie header for the cookie to the response, like
+// http.SetCookie, followed by the Priority attribute which http.Cookie does
+// not support.
+func setCookie(w http.ResponseWriter, cookie *http.Cookie, priority Priority) {
+	v := cookie.String()
+	if v == "" {
+		return
+	}
+	if p := priority.String(); p != "" {
+		v += "; Priority=" + p
+	}
+	w.Header().Add("Set-Cookie", v)
+}
@@ -4,11 +4,14 @@ import (
 	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 var unsignedManager = NewManager()
 var signedManager = NewManager(WithSigningKey([]byte("super-secret-key")))
+var encryptedManager = NewManager(WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")))
 
 func TestManager_Get(t *testing.T) {
 	r, _ := http.NewRequest(http.MethodGet, "/", nil)
@@ -267,6 +270,72 @@ func TestManager_Remove(t *testing.T) {
 	}
 }
 
+func TestManager_Set_PartitionedAndPriority(t *testing.T) {
+	tests := map[string]struct {
+		opts     Options
+		expected string
+	}{
+		"partitioned": {
+			opts:     Options{Partitioned: true},
+			expected: "myCookie=myValue; Secure; Partitioned",
+		},
+		"priority": {
+			opts:     Options{Priority: PriorityHigh},
+			expected: "myCookie=myValue; Priority=High",
+		},
+		"both": {
+			opts:     Options{Path: "/", Secure: true, Partitioned: true, Priority: PriorityLow},
+			expected: "myCookie=myValue; Path=/; Secure; Partitioned; Priority=Low",
+		},
+		"default priority": {
+			opts:     Options{Priority: PriorityDefault},
+			expected: "myCookie=myValue",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := unsignedManager.Set(w, "myCookie", "myValue", tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if header := w.Header().Get("Set-Cookie"); header != tt.expected {
+				t.Errorf("Expected Set-Cookie '%s', but got '%s'", tt.expected, header)
+			}
+		})
+	}
+}
+
+func TestManager_Remove_PartitionedAndPriority(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := unsignedManager.Remove(w, "myCookie", Options{Partitioned: true, Priority: PriorityMedium})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	header := w.Header().Get("Set-Cookie")
+	for _, attr := range []string{"Max-Age=0", "Secure", "Partitioned", "Priority=Medium"} {
+		if !strings.Contains(header, "; "+attr) {
+			t.Errorf("Expected Set-Cookie to contain '%s', but got '%s'", attr, header)
+		}
+	}
+}
+
+func TestPriority_String(t *testing.T) {
+	tests := map[Priority]string{
+		PriorityDefault: "",
+		PriorityLow:     "Low",
+		PriorityMedium:  "Medium",
+		PriorityHigh:    "High",
+		Priority(42):    "",
+	}
+	for priority, expected := range tests {
+		if priority.String() != expected {
+			t.Errorf("Expected '%s', but got '%s'", expected, priority.String())
+		}
+	}
+}
+
 func TestManager_RemoveWithOptions(t *testing.T) {
 	w := httptest.NewRecorder()
 
@@ -296,3 +365,199 @@ func TestManager_RemoveWithOptions(t *testing.T) {
 		t.Errorf("Expected cookie path '%s', but got '%s'", path, cookie.Path)
 	}
 }
+
+func TestManager_SetSigned_Keyring(t *testing.T) {
+	manager := NewManager(WithKeyring("v2", map[string][]byte{
+		"v1": []byte("old-secret-key"),
+		"v2": []byte("new-secret-key"),
+	}))
+
+	w := httptest.NewRecorder()
+
+	err := manager.SetSigned(w, "myCookie", "myValue")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	value, err := manager.GetSigned(r, "myCookie")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+}
+
+func TestManager_SetEncrypted(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	cookieName := "myCookie"
+	expectedValue := "myValue"
+
+	err := encryptedManager.SetEncrypted(w, cookieName, expectedValue)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, but got %d", len(cookies))
+	}
+
+	cookie := cookies[0]
+	if cookie.Value == expectedValue || strings.Contains(cookie.Value, base64.URLEncoding.EncodeToString([]byte(expectedValue))) {
+		t.Errorf("Expected cookie value to be encrypted, but got '%s'", cookie.Value)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookie)
+
+	value, err := encryptedManager.GetEncrypted(r, cookieName)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != expectedValue {
+		t.Errorf("Expected value '%s', but got '%s'", expectedValue, value)
+	}
+}
+
+func TestManager_SetEncrypted_NoKey(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := unsignedManager.SetEncrypted(w, "myCookie", "myValue")
+	if err != ErrEncryptionKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrEncryptionKeyRequired, err)
+	}
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be written")
+	}
+}
+
+func TestManager_SetSigned_NoKey(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := unsignedManager.SetSigned(w, "myCookie", "myValue")
+	if err != ErrSigningKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningKeyRequired, err)
+	}
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be written")
+	}
+}
+
+func TestManager_GetEncrypted_NoKey(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "myCookie", Value: "myValue"})
+
+	_, err := unsignedManager.GetEncrypted(r, "myCookie")
+	if err != ErrEncryptionKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrEncryptionKeyRequired, err)
+	}
+}
+
+func TestManager_GetEncrypted_Error(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+
+	_, err := encryptedManager.GetEncrypted(r, "myCookie")
+	if err != http.ErrNoCookie {
+		t.Errorf("Expected error '%v', but got '%v'", http.ErrNoCookie, err)
+	}
+}
+
+func TestManager_GetEncrypted_SwappedName(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := encryptedManager.SetEncrypted(w, "User-ID", "12345")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "Is-Admin", Value: w.Result().Cookies()[0].Value})
+
+	_, err = encryptedManager.GetEncrypted(r, "Is-Admin")
+	if err != ErrInvalidEncryptedCookie {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidEncryptedCookie, err)
+	}
+}
+
+func TestWithEncryptionKey_InvalidLength(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic, but got none")
+		}
+	}()
+	WithEncryptionKey([]byte("too-short"))
+}
+
+func TestManager_GetSigned_MaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithSignatureMaxAge(time.Hour),
+		WithClock(func() time.Time { return now }),
+	)
+
+	w := httptest.NewRecorder()
+	err := manager.SetSigned(w, "myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	now = now.Add(30 * time.Minute)
+
+	value, err := manager.GetSigned(r, "myCookie")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+
+	_, err = manager.GetSigned(r, "myCookie", 10*time.Minute)
+	if err != ErrSignatureExpired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSignatureExpired, err)
+	}
+
+	now = now.Add(time.Hour)
+
+	_, err = manager.GetSigned(r, "myCookie")
+	if err != ErrSignatureExpired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSignatureExpired, err)
+	}
+}
+
+func TestManager_Encode(t *testing.T) {
+	value, err := signedManager.Encode("myCookie", "myValue")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+
+	value, err = signedManager.Encode("myCookie", "myValue", Options{Signed: true})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expectedValue := signCookieValue("myValue", signedManager.signingKey)
+	if value != expectedValue {
+		t.Errorf("Expected value '%s', but got '%s'", expectedValue, value)
+	}
+
+	_, err = signedManager.Encode("myCookie", "myValue", Options{Encrypted: true})
+	if err != ErrEncryptionKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrEncryptionKeyRequired, err)
+	}
+}
@@ -0,0 +1,317 @@
+// Package csrf provides Cross-Site Request Forgery protection using signed
+// double-submit cookies issued through a cookie.Manager.
+//
+// A random token is stored in a signed cookie, and must be submitted back in a
+// header or form field on every unsafe request. When the request carries a
+// session, the token is bound to it, so a token cannot be reused across
+// sessions. Requests whose session cookie cannot be verified are not issued a
+// token, and fail verification.
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/syntaqx/cookie"
+)
+
+// DefaultCookieName is the default name of the cookie storing the token.
+const DefaultCookieName = "_csrf"
+
+// DefaultHeaderName is the default name of the header carrying the token.
+const DefaultHeaderName = "X-CSRF-Token"
+
+// DefaultFieldName is the default name of the form field carrying the token.
+const DefaultFieldName = "csrf_token"
+
+// DefaultSessionCookieName is the default name of the session cookie tokens are
+// bound to.
+const DefaultSessionCookieName = "session"
+
+// contextKey is the type of the keys of the values stored in the request
+// context.
+type contextKey int
+
+const (
+	tokenKey contextKey = iota
+	failureKey
+)
+
+// Middleware protects handlers against Cross-Site Request Forgery.
+type Middleware struct {
+	cookies           *cookie.Manager
+	cookieName        string
+	headerName        string
+	fieldName         string
+	sessionCookieName string
+	bind              func(r *http.Request) (string, error)
+	exemptPaths       []string
+	failureHandler    http.Handler
+	options           cookie.Options
+}
+
+// Option is a function type for configuring the Middleware.
+type Option func(*Middleware)
+
+// WithCookieName sets the name of the cookie storing the token.
+func WithCookieName(name string) Option {
+	return func(m *Middleware) {
+		m.cookieName = name
+	}
+}
+
+// WithHeaderName sets the name of the header carrying the token.
+func WithHeaderName(name string) Option {
+	return func(m *Middleware) {
+		m.headerName = name
+	}
+}
+
+// WithFieldName sets the name of the form field carrying the token.
+func WithFieldName(name string) Option {
+	return func(m *Middleware) {
+		m.fieldName = name
+	}
+}
+
+// WithSessionCookieName sets the name of the session cookie tokens are bound
+// to. The cookie must be signed, and its verified value is the session the
+// token is bound to. Unsafe requests carrying a session cookie that cannot be
+// verified, such as an encrypted one, fail with ErrSessionInvalid. It is
+// ignored when WithBinding is used.
+func WithSessionCookieName(name string) Option {
+	return func(m *Middleware) {
+		m.sessionCookieName = name
+	}
+}
+
+// WithBinding sets the function returning the value identifying the session of
+// a request tokens are bound to, or an empty string when it has none. The value
+// must stay the same for as long as the session lives, such as the one returned
+// by session.Manager.Binding:
+//
+//	csrf.New(cookies, csrf.WithBinding(sessions.Binding))
+func WithBinding(bind func(r *http.Request) string) Option {
+	return func(m *Middleware) {
+		m.bind = func(r *http.Request) (string, error) {
+			return bind(r), nil
+		}
+	}
+}
+
+// WithExemptPaths exempts requests to the given paths from verification. A
+// path ending in "/" exempts every path below it.
+func WithExemptPaths(paths ...string) Option {
+	return func(m *Middleware) {
+		m.exemptPaths = append(m.exemptPaths, paths...)
+	}
+}
+
+// WithFailureHandler sets the handler called when verification fails. The
+// reason can be retrieved with FailureReason. It defaults to responding with
+// 403 Forbidden.
+func WithFailureHandler(handler http.Handler) Option {
+	return func(m *Middleware) {
+		m.failureHandler = handler
+	}
+}
+
+// WithCookieOptions sets the options of the cookie storing the token. The
+// cookie is always signed rather than encrypted, and its SameSite attribute
+// defaults to Lax when not set.
+func WithCookieOptions(opts cookie.Options) Option {
+	return func(m *Middleware) {
+		m.options = opts
+	}
+}
+
+// New creates a new Middleware issuing tokens through cookies, which must have
+// a signing key. It panics if cookies cannot sign, as every unsafe request
+// would otherwise be rejected. By default the token cookie is Secure, HttpOnly
+// and SameSite Lax.
+func New(cookies *cookie.Manager, opts ...Option) *Middleware {
+	if !cookies.CanSign() {
+		panic("csrf: cookie manager must have a signing key")
+	}
+
+	m := &Middleware{
+		cookies:           cookies,
+		cookieName:        DefaultCookieName,
+		headerName:        DefaultHeaderName,
+		fieldName:         DefaultFieldName,
+		sessionCookieName: DefaultSessionCookieName,
+		failureHandler:    http.HandlerFunc(defaultFailureHandler),
+		options: cookie.Options{
+			Path:     "/",
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.bind == nil {
+		m.bind = m.sessionCookieBinding
+	}
+	m.options.Signed, m.options.Encrypted = true, false
+	if m.options.SameSite == 0 || m.options.SameSite == http.SameSiteDefaultMode {
+		m.options.SameSite = http.SameSiteLaxMode
+	}
+	return m
+}
+
+// Handler wraps next, issuing a token to every request and verifying it on
+// requests with unsafe methods.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// No token is issued when the session cannot be identified, so that
+		// it is never left unbound.
+		binding, err := m.binding(r)
+		var token string
+		if err == nil {
+			token, err = m.readToken(r, binding)
+			if err != nil {
+				token, err = newToken()
+				if err == nil {
+					err = m.cookies.Set(w, m.cookieName, token+"."+binding, m.options)
+				}
+				if err != nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+				if !isSafeMethod(r.Method) {
+					err = ErrTokenInvalid
+				}
+			}
+			r = r.WithContext(context.WithValue(r.Context(), tokenKey, token))
+		}
+
+		if isSafeMethod(r.Method) || m.isExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err == nil {
+			err = m.verify(r, token)
+		}
+		if err != nil {
+			r = r.WithContext(context.WithValue(r.Context(), failureKey, err))
+			m.failureHandler.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// readToken returns the token stored in the cookie of the request, provided it
+// is bound to the given binding.
+func (m *Middleware) readToken(r *http.Request, binding string) (string, error) {
+	value, err := m.cookies.GetSigned(r, m.cookieName)
+	if err != nil {
+		return "", err
+	}
+
+	token, tokenBinding, ok := strings.Cut(value, ".")
+	if !ok || token == "" || subtle.ConstantTimeCompare([]byte(tokenBinding), []byte(binding)) != 1 {
+		return "", ErrTokenInvalid
+	}
+	return token, nil
+}
+
+// verify checks the token submitted with the request matches the given token.
+func (m *Middleware) verify(r *http.Request, token string) error {
+	submitted := r.Header.Get(m.headerName)
+	if submitted == "" {
+		submitted = r.PostFormValue(m.fieldName)
+	}
+	if submitted == "" {
+		return ErrTokenMissing
+	}
+	if subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) != 1 {
+		return ErrTokenMismatch
+	}
+	return nil
+}
+
+// binding returns the value tokens are bound to for the request, derived from
+// its session, or an empty string when it has none. The session is hashed so
+// it is not revealed by the token cookie.
+func (m *Middleware) binding(r *http.Request) (string, error) {
+	session, err := m.bind(r)
+	if err != nil || session == "" {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(session))
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// sessionCookieBinding returns the verified value of the signed session cookie
+// of the request, or an empty string when it has none. It returns
+// ErrSessionInvalid when the cookie cannot be verified, rather than leaving
+// tokens unbound.
+func (m *Middleware) sessionCookieBinding(r *http.Request) (string, error) {
+	value, err := m.cookies.GetSigned(r, m.sessionCookieName)
+	if err == http.ErrNoCookie {
+		return "", nil
+	}
+	if err != nil {
+		return "", ErrSessionInvalid
+	}
+	return value, nil
+}
+
+// isExempt reports whether the path is exempt from verification.
+func (m *Middleware) isExempt(path string) bool {
+	for _, exempt := range m.exemptPaths {
+		if path == exempt || (strings.HasSuffix(exempt, "/") && strings.HasPrefix(path, exempt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Token returns the token of the request, to be submitted back in a header or
+// form field. It returns an empty string if the request was not handled by
+// the Middleware.
+func Token(r *http.Request) string {
+	token, _ := r.Context().Value(tokenKey).(string)
+	return token
+}
+
+// FailureReason returns the reason verification failed, for use in a failure
+// handler.
+func FailureReason(r *http.Request) error {
+	err, _ := r.Context().Value(failureKey).(error)
+	return err
+}
+
+// isSafeMethod reports whether the method is considered safe by RFC 9110, and
+// so is not verified.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// newToken generates a random token.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// defaultFailureHandler responds with 403 Forbidden.
+func defaultFailureHandler(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+}
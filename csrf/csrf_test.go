@@ -0,0 +1,416 @@
+package csrf
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syntaqx/cookie"
+	"github.com/syntaqx/cookie/session"
+)
+
+var cookieManager = cookie.NewManager(cookie.WithSigningKey([]byte("super-secret-key")))
+
+// okHandler responds with the token of the request.
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(Token(r)))
+})
+
+// issue performs a safe request through the handler, returning the token and
+// the cookie storing it.
+func issue(t *testing.T, handler http.Handler, cookies ...*http.Cookie) (string, *http.Cookie) {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, w.Code)
+	}
+
+	for _, c := range w.Result().Cookies() {
+		if c.Name == DefaultCookieName {
+			return w.Body.String(), c
+		}
+	}
+	t.Fatal("Expected token cookie to be issued")
+	return "", nil
+}
+
+func TestMiddleware_IssuesToken(t *testing.T) {
+	handler := New(cookieManager).Handler(okHandler)
+
+	token, c := issue(t, handler)
+	if token == "" {
+		t.Error("Expected token to be available to the handler")
+	}
+	if !c.Secure || !c.HttpOnly || c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Expected token cookie to be Secure, HttpOnly and SameSite Lax, but got %v", c)
+	}
+	if strings.Contains(c.Value, token) {
+		t.Error("Expected token cookie to be signed")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(c)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Body.String() != token {
+		t.Errorf("Expected token '%s' to be reused, but got '%s'", token, w.Body.String())
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no new token cookie to be issued")
+	}
+}
+
+func TestMiddleware_Verify(t *testing.T) {
+	handler := New(cookieManager).Handler(okHandler)
+	token, c := issue(t, handler)
+
+	tests := map[string]struct {
+		header string
+		form   string
+		cookie *http.Cookie
+		status int
+	}{
+		"header":        {header: token, cookie: c, status: http.StatusOK},
+		"form":          {form: token, cookie: c, status: http.StatusOK},
+		"missing token": {cookie: c, status: http.StatusForbidden},
+		"wrong token":   {header: "wrong", cookie: c, status: http.StatusForbidden},
+		"no cookie":     {header: token, status: http.StatusForbidden},
+		"forged cookie": {header: token, cookie: &http.Cookie{Name: DefaultCookieName, Value: token + "."}, status: http.StatusForbidden},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			form := url.Values{}
+			if tt.form != "" {
+				form.Set(DefaultFieldName, tt.form)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.header != "" {
+				r.Header.Set(DefaultHeaderName, tt.header)
+			}
+			if tt.cookie != nil {
+				r.AddCookie(tt.cookie)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, but got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+// sessionCookie returns a session cookie signed by cookieManager.
+func sessionCookie(t *testing.T, id string) *http.Cookie {
+	t.Helper()
+
+	value, err := cookieManager.Encode(DefaultSessionCookieName, id, cookie.Options{Signed: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return &http.Cookie{Name: DefaultSessionCookieName, Value: value}
+}
+
+func TestMiddleware_SessionBinding(t *testing.T) {
+	handler := New(cookieManager).Handler(okHandler)
+
+	session := sessionCookie(t, "session-a")
+	token, c := issue(t, handler, session)
+
+	post := func(session *http.Cookie) int {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.Header.Set(DefaultHeaderName, token)
+		r.AddCookie(c)
+		if session != nil {
+			r.AddCookie(session)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if status := post(session); status != http.StatusOK {
+		t.Errorf("Expected status %d, but got %d", http.StatusOK, status)
+	}
+	if status := post(sessionCookie(t, "session-b")); status != http.StatusForbidden {
+		t.Errorf("Expected token bound to another session to be rejected, but got %d", status)
+	}
+	if status := post(nil); status != http.StatusForbidden {
+		t.Errorf("Expected token bound to a session to be rejected without it, but got %d", status)
+	}
+	if status := post(&http.Cookie{Name: DefaultSessionCookieName, Value: "session-a"}); status != http.StatusForbidden {
+		t.Errorf("Expected token bound to a session to be rejected with an unsigned session cookie, but got %d", status)
+	}
+}
+
+func TestMiddleware_SessionBinding_Unverifiable(t *testing.T) {
+	keyed := cookie.NewManager(
+		cookie.WithSigningKey([]byte("super-secret-key")),
+		cookie.WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")),
+	)
+	value, err := keyed.Encode(DefaultSessionCookieName, "session-a", cookie.Options{Encrypted: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session := &http.Cookie{Name: DefaultSessionCookieName, Value: value}
+
+	var reason error
+	failure := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reason = FailureReason(r)
+		w.WriteHeader(http.StatusForbidden)
+	})
+	handler := New(keyed, WithFailureHandler(failure)).Handler(okHandler)
+
+	// An encrypted session cookie cannot be verified without WithBinding, so
+	// no token is issued.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(session)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "" || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected no token to be issued, but got %d %q %v", w.Code, w.Body.String(), w.Result().Cookies())
+	}
+
+	// Tokens issued without a session are not accepted along with it either.
+	token, c := issue(t, handler)
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(DefaultHeaderName, token)
+	r.AddCookie(c)
+	r.AddCookie(session)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, but got %d", http.StatusForbidden, w.Code)
+	}
+	if reason != ErrSessionInvalid {
+		t.Errorf("Expected reason '%v', but got '%v'", ErrSessionInvalid, reason)
+	}
+}
+
+func TestMiddleware_SessionBinding_SessionRewritten(t *testing.T) {
+	// The clock advances on every call, so each signature carries a new
+	// timestamp.
+	now := time.Now()
+	timestamped := cookie.NewManager(
+		cookie.WithSigningKey([]byte("super-secret-key")),
+		cookie.WithSignatureMaxAge(time.Hour),
+		cookie.WithClock(func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}),
+	)
+	encrypted := cookie.NewManager(cookie.WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")))
+
+	tests := map[string]struct {
+		cookies    *cookie.Manager
+		store      session.Store
+		options    cookie.Options
+		useBinding bool
+	}{
+		"memory store":           {cookies: timestamped, store: session.NewMemoryStore(0)},
+		"memory store binding":   {cookies: timestamped, store: session.NewMemoryStore(0), useBinding: true},
+		"cookie store binding":   {cookies: cookieManager, store: session.NewCookieStore(), useBinding: true},
+		"encrypted cookie store": {cookies: encrypted, store: session.NewCookieStore(), options: cookie.Options{Encrypted: true}, useBinding: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sessions := session.NewManager(tt.cookies, tt.store, session.WithCookieOptions(tt.options))
+
+			var opts []Option
+			if tt.useBinding {
+				opts = append(opts, WithBinding(sessions.Binding))
+			}
+			// Every request writes the session, counting the requests.
+			handler := New(cookieManager, opts...).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				s, err := sessions.Get(r)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				count, _ := session.Value[int](s, "count")
+				s.Set("count", count+1)
+				if err := sessions.Save(w, s); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				w.Write([]byte(Token(r)))
+			}))
+
+			jar := map[string]*http.Cookie{}
+			do := func(r *http.Request) *httptest.ResponseRecorder {
+				for _, c := range jar {
+					r.AddCookie(c)
+				}
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				for _, c := range w.Result().Cookies() {
+					jar[c.Name] = c
+				}
+				return w
+			}
+
+			// The first request creates the session, and the second issues a
+			// token bound to it, before the session is rewritten again.
+			do(httptest.NewRequest(http.MethodGet, "/", nil))
+			token := do(httptest.NewRequest(http.MethodGet, "/", nil)).Body.String()
+			before := jar[session.DefaultCookieName].Value
+			do(httptest.NewRequest(http.MethodGet, "/", nil))
+			if jar[session.DefaultCookieName].Value == before {
+				t.Fatal("Expected session cookie to be rewritten")
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.Header.Set(DefaultHeaderName, token)
+			if w := do(r); w.Code != http.StatusOK {
+				t.Errorf("Expected status %d, but got %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ExemptPaths(t *testing.T) {
+	handler := New(cookieManager, WithExemptPaths("/webhook", "/api/public/")).Handler(okHandler)
+
+	tests := map[string]int{
+		"/webhook":         http.StatusOK,
+		"/webhook/other":   http.StatusForbidden,
+		"/api/public/ping": http.StatusOK,
+		"/api/private":     http.StatusForbidden,
+	}
+
+	for path, status := range tests {
+		t.Run(path, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, path, nil)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != status {
+				t.Errorf("Expected status %d, but got %d", status, w.Code)
+			}
+		})
+	}
+}
+
+func TestMiddleware_FailureHandler(t *testing.T) {
+	var reason error
+	failure := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reason = FailureReason(r)
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	handler := New(cookieManager, WithFailureHandler(failure)).Handler(okHandler)
+	_, c := issue(t, handler)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(c)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("Expected status %d, but got %d", http.StatusTeapot, w.Code)
+	}
+	if reason != ErrTokenMissing {
+		t.Errorf("Expected reason '%v', but got '%v'", ErrTokenMissing, reason)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set(DefaultHeaderName, "wrong")
+	r.AddCookie(c)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if reason != ErrTokenMismatch {
+		t.Errorf("Expected reason '%v', but got '%v'", ErrTokenMismatch, reason)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	if reason != ErrTokenInvalid {
+		t.Errorf("Expected reason '%v', but got '%v'", ErrTokenInvalid, reason)
+	}
+}
+
+func TestMiddleware_Options(t *testing.T) {
+	handler := New(cookieManager,
+		WithCookieName("xsrf"),
+		WithHeaderName("X-XSRF-Token"),
+		WithFieldName("xsrf"),
+		WithSessionCookieName("sid"),
+		WithCookieOptions(cookie.Options{Path: "/app"}),
+	).Handler(okHandler)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	c := w.Result().Cookies()[0]
+	if c.Name != "xsrf" || c.Path != "/app" {
+		t.Errorf("Expected token cookie to use the configured options, but got %v", c)
+	}
+	if c.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Expected SameSite to default to Lax, but got %v", c.SameSite)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("X-XSRF-Token", w.Body.String())
+	r.AddCookie(c)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, but got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestMiddleware_SetError(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	verifyOnly := cookie.NewManager(cookie.WithSigner(cookie.NewEd25519Verifier(publicKey)))
+	handler := New(verifyOnly).Handler(okHandler)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, but got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestNew_NoSigningKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic, but got none")
+		}
+	}()
+	New(cookie.NewManager())
+}
+
+func TestToken_NoMiddleware(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if token := Token(r); token != "" {
+		t.Errorf("Expected no token, but got '%s'", token)
+	}
+	if reason := FailureReason(r); reason != nil {
+		t.Errorf("Expected no reason, but got '%v'", reason)
+	}
+}
@@ -0,0 +1,19 @@
+package csrf
+
+import "errors"
+
+// ErrTokenMissing is returned when an unsafe request does not submit a token.
+var ErrTokenMissing = errors.New("csrf token missing")
+
+// ErrTokenInvalid is returned when the token cookie is missing, forged, or
+// bound to another session.
+var ErrTokenInvalid = errors.New("csrf token invalid")
+
+// ErrTokenMismatch is returned when the submitted token does not match the
+// token cookie.
+var ErrTokenMismatch = errors.New("csrf token mismatch")
+
+// ErrSessionInvalid is returned when the request carries a session cookie that
+// cannot be verified, so the token cannot be bound to its session. Encrypted
+// session cookies require WithBinding.
+var ErrSessionInvalid = errors.New("csrf session cookie invalid")
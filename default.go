@@ -1,6 +1,9 @@
 package cookie
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 // DefaultManager is the default cookie manager exposed by this package.
 var DefaultManager = NewManager()
@@ -10,9 +13,14 @@ func Get(r *http.Request, name string) (string, error) {
 	return DefaultManager.Get(r, name)
 }
 
-// GetSigned retrieves a signed cookie value.
-func GetSigned(r *http.Request, name string) (string, error) {
-	return DefaultManager.GetSigned(r, name)
+// GetSigned retrieves a signed cookie value, optionally enforcing a max age.
+func GetSigned(r *http.Request, name string, maxAge ...time.Duration) (string, error) {
+	return DefaultManager.GetSigned(r, name, maxAge...)
+}
+
+// GetEncrypted retrieves an encrypted cookie value.
+func GetEncrypted(r *http.Request, name string) (string, error) {
+	return DefaultManager.GetEncrypted(r, name)
 }
 
 // Set sets the value of a cookie.
@@ -25,12 +33,43 @@ func SetSigned(w http.ResponseWriter, name, value string, opts ...Options) error
 	return DefaultManager.SetSigned(w, name, value, opts...)
 }
 
-// Remove removes a cookie from the response.
-func Remove(w http.ResponseWriter, name string) error {
-	return DefaultManager.Remove(w, name)
+// SetEncrypted sets an encrypted value of a cookie.
+func SetEncrypted(w http.ResponseWriter, name, value string, opts ...Options) error {
+	return DefaultManager.SetEncrypted(w, name, value, opts...)
+}
+
+// SetJSON sets the value of a cookie to the JSON encoding of v.
+func SetJSON(w http.ResponseWriter, name string, v interface{}, opts ...Options) error {
+	return DefaultManager.SetJSON(w, name, v, opts...)
+}
+
+// GetJSON retrieves a cookie set by SetJSON and decodes its value into v.
+func GetJSON(r *http.Request, name string, v interface{}, opts ...Options) error {
+	return DefaultManager.GetJSON(r, name, v, opts...)
+}
+
+// Remove removes a cookie from the response. The options should match those
+// it was set with, such as Path, Domain and Partitioned.
+func Remove(w http.ResponseWriter, name string, opts ...Options) error {
+	return DefaultManager.Remove(w, name, opts...)
 }
 
 // PopulateFromCookies populates a struct with cookie values.
 func PopulateFromCookies(r *http.Request, dest interface{}) error {
 	return DefaultManager.PopulateFromCookies(r, dest)
 }
+
+// WriteToCookies writes the tagged fields of a struct as cookies.
+func WriteToCookies(w http.ResponseWriter, src interface{}, opts ...Options) error {
+	return DefaultManager.WriteToCookies(w, src, opts...)
+}
+
+// AddFlash adds a flash message to be read by a later request.
+func AddFlash(w http.ResponseWriter, r *http.Request, kind FlashKind, message string) error {
+	return DefaultManager.AddFlash(w, r, kind, message)
+}
+
+// Flashes returns the flash messages of the request, and removes them.
+func Flashes(w http.ResponseWriter, r *http.Request) ([]Flash, error) {
+	return DefaultManager.Flashes(w, r)
+}
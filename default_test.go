@@ -156,6 +156,20 @@ func TestRemove(t *testing.T) {
 	}
 }
 
+func TestRemove_WithOptions(t *testing.T) {
+	rr := httptest.NewRecorder()
+
+	err := Remove(rr, "__Host-cookieName", Options{Path: "/", Secure: true, Partitioned: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookie := rr.Result().Cookies()[0]
+	if cookie.Name != "__Host-cookieName" || cookie.Path != "/" || !cookie.Secure || !cookie.Partitioned {
+		t.Errorf("Expected cookie to be removed with the given options, but got %+v", cookie)
+	}
+}
+
 func TestPopulateFromCookies(t *testing.T) {
 	DefaultManager = signedManager
 	value := "test"
@@ -180,3 +194,89 @@ func TestPopulateFromCookies(t *testing.T) {
 		t.Errorf("Expected value '%s', but got '%s'", expected.Default, dest.Default)
 	}
 }
+
+func TestSetEncrypted_GetEncrypted(t *testing.T) {
+	DefaultManager = encryptedManager
+
+	w := httptest.NewRecorder()
+
+	err := SetEncrypted(w, "myCookie", "myValue")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	value, err := GetEncrypted(req, "myCookie")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+}
+
+func TestWriteToCookies(t *testing.T) {
+	DefaultManager = unsignedManager
+
+	type MyStruct struct {
+		Default string `cookie:"cookie1"`
+	}
+
+	w := httptest.NewRecorder()
+	err := WriteToCookies(w, MyStruct{Default: "test"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "cookie1" || cookie.Value != "test" {
+		t.Errorf("Unexpected cookie: %v", cookie)
+	}
+}
+
+func TestAddFlash_Flashes(t *testing.T) {
+	DefaultManager = signedManager
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	if err := AddFlash(w, req, FlashInfo, "Saved"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	flashes, err := Flashes(httptest.NewRecorder(), req)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if len(flashes) != 1 || flashes[0].Message != "Saved" {
+		t.Errorf("Unexpected flashes: %v", flashes)
+	}
+}
+
+func TestSetJSON_GetJSON(t *testing.T) {
+	DefaultManager = unsignedManager
+
+	w := httptest.NewRecorder()
+	if err := SetJSON(w, "prefs", map[string]string{"theme": "dark"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	var prefs map[string]string
+	if err := GetJSON(req, "prefs", &prefs); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if prefs["theme"] != "dark" {
+		t.Errorf("Expected value '%s', but got '%s'", "dark", prefs["theme"])
+	}
+}
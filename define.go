@@ -0,0 +1,94 @@
+package cookie
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Definition is a cookie declared once with a fixed name, value type and
+// options, so that every handler reads and writes it the same way.
+type Definition[T any] struct {
+	name    string
+	opts    Options
+	manager *Manager
+}
+
+// Define declares a cookie holding values of type T, using DefaultManager. T
+// can be any type supported by PopulateFromCookies. The options, including
+// whether the cookie is signed or encrypted, are used by every call to Get, Set
+// and Remove.
+//
+//	var Theme = cookie.Define[string]("THEME", cookie.Options{HttpOnly: true})
+func Define[T any](name string, opts Options) *Definition[T] {
+	return &Definition[T]{name: name, opts: opts}
+}
+
+// DefineWith is like Define, but uses the given Manager, along with its keys
+// and custom type handlers.
+func DefineWith[T any](m *Manager, name string, opts Options) *Definition[T] {
+	return &Definition[T]{name: name, opts: opts, manager: m}
+}
+
+// Name returns the name of the cookie.
+func (d *Definition[T]) Name() string {
+	return d.name
+}
+
+// Options returns the options of the cookie.
+func (d *Definition[T]) Options() Options {
+	return d.opts
+}
+
+// Get retrieves the value of the cookie, verifying or decrypting it according
+// to the options of the definition.
+func (d *Definition[T]) Get(r *http.Request) (T, error) {
+	var value T
+	m := d.getManager()
+
+	var raw string
+	var err error
+	switch {
+	case d.opts.Encrypted:
+		raw, err = m.GetEncrypted(r, d.name)
+	case d.opts.Signed:
+		raw, err = m.GetSigned(r, d.name)
+	default:
+		raw, err = m.Get(r, d.name)
+	}
+	if err != nil {
+		return value, err
+	}
+
+	if err := m.setFieldValue(reflect.ValueOf(&value).Elem(), raw, ","); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// Set sets the value of the cookie. It returns ErrSigningKeyRequired or
+// ErrEncryptionKeyRequired when the Manager lacks the key the options of the
+// definition call for, rather than writing the value as is.
+func (d *Definition[T]) Set(w http.ResponseWriter, value T) error {
+	m := d.getManager()
+
+	raw, err := m.formatFieldValue(reflect.ValueOf(&value).Elem(), ",")
+	if err != nil {
+		return err
+	}
+	return m.Set(w, d.name, raw, d.opts)
+}
+
+// Remove removes the cookie from the response.
+func (d *Definition[T]) Remove(w http.ResponseWriter) error {
+	return d.getManager().Remove(w, d.name, d.opts)
+}
+
+// getManager returns the Manager of the definition, which is DefaultManager
+// unless one was given to DefineWith.
+func (d *Definition[T]) getManager() *Manager {
+	if d.manager == nil {
+		return DefaultManager
+	}
+	return d.manager
+}
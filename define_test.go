@@ -0,0 +1,169 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefinition_SetGet(t *testing.T) {
+	DefaultManager = signedManager
+
+	since := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	theme := Define[string]("theme", Options{HttpOnly: true})
+	pageSize := Define[int]("page_size", Options{Signed: true})
+	tags := Define[[]string]("tags", Options{})
+	sinceCookie := Define[time.Time]("since", Options{})
+	ratio := Define[*float64]("ratio", Options{})
+
+	w := httptest.NewRecorder()
+	r := 0.5
+	for _, err := range []error{
+		theme.Set(w, "dark"),
+		pageSize.Set(w, 50),
+		tags.Set(w, []string{"a", "b"}),
+		sinceCookie.Set(w, since),
+		ratio.Set(w, &r),
+	} {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	cookies := w.Result().Cookies()
+	if !cookies[0].HttpOnly {
+		t.Error("Expected cookie to be HttpOnly")
+	}
+	if cookies[1].Value != signCookieValue("50", signedManager.signingKey) {
+		t.Errorf("Expected signed cookie value, but got '%s'", cookies[1].Value)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	if value, err := theme.Get(req); err != nil || value != "dark" {
+		t.Errorf("Expected value 'dark', but got '%s' (%v)", value, err)
+	}
+	if value, err := pageSize.Get(req); err != nil || value != 50 {
+		t.Errorf("Expected value 50, but got %d (%v)", value, err)
+	}
+	if value, err := tags.Get(req); err != nil || !reflect.DeepEqual(value, []string{"a", "b"}) {
+		t.Errorf("Expected value [a b], but got %v (%v)", value, err)
+	}
+	if value, err := sinceCookie.Get(req); err != nil || !value.Equal(since) {
+		t.Errorf("Expected value %v, but got %v (%v)", since, value, err)
+	}
+	if value, err := ratio.Get(req); err != nil || value == nil || *value != r {
+		t.Errorf("Expected value %v, but got %v (%v)", r, value, err)
+	}
+}
+
+func TestDefinition_GetErrors(t *testing.T) {
+	pageSize := DefineWith[int](signedManager, "page_size", Options{Signed: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if _, err := pageSize.Get(req); err != http.ErrNoCookie {
+		t.Errorf("Expected error '%v', but got '%v'", http.ErrNoCookie, err)
+	}
+
+	req.AddCookie(&http.Cookie{Name: "page_size", Value: "50"})
+	if _, err := pageSize.Get(req); err == nil {
+		t.Error("Expected error for unsigned value, but got nil")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "page_size", Value: signCookieValue("invalid", signedManager.signingKey)})
+	value, err := pageSize.Get(req)
+	if err == nil {
+		t.Error("Expected error for invalid integer, but got nil")
+	}
+	if value != 0 {
+		t.Errorf("Expected zero value, but got %d", value)
+	}
+}
+
+func TestDefinition_Encrypted(t *testing.T) {
+	secret := DefineWith[string](encryptedManager, "secret", Options{Encrypted: true})
+
+	w := httptest.NewRecorder()
+	if err := secret.Set(w, "value"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Value == "value" {
+		t.Error("Expected cookie value to be encrypted")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	if value, err := secret.Get(req); err != nil || value != "value" {
+		t.Errorf("Expected value 'value', but got '%s' (%v)", value, err)
+	}
+}
+
+func TestDefinition_NoKey(t *testing.T) {
+	tests := map[string]struct {
+		opts     Options
+		expected error
+	}{
+		"signed":    {Options{Signed: true}, ErrSigningKeyRequired},
+		"encrypted": {Options{Encrypted: true}, ErrEncryptionKeyRequired},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := DefineWith[string](unsignedManager, "secret", tt.opts).Set(w, "value")
+			if err != tt.expected {
+				t.Errorf("Expected error '%v', but got '%v'", tt.expected, err)
+			}
+			if len(w.Result().Cookies()) != 0 {
+				t.Error("Expected no cookie to be written")
+			}
+		})
+	}
+}
+
+func TestDefinition_CustomHandler(t *testing.T) {
+	manager := NewManager(
+		WithCustomHandler(reflect.TypeOf(CustomType{}), func(value string) (interface{}, error) {
+			return CustomTypeErrorMaker(value)
+		}),
+	)
+	custom := DefineWith[CustomType](manager, "custom", Options{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "custom", Value: "value"})
+
+	_, err := custom.Get(req)
+	if err == nil || err.Error() != "just a big ol fail" {
+		t.Errorf("Expected error from custom handler, but got '%v'", err)
+	}
+}
+
+func TestDefinition_Remove(t *testing.T) {
+	theme := DefineWith[string](unsignedManager, "theme", Options{Path: "/app"})
+
+	if theme.Name() != "theme" {
+		t.Errorf("Expected name '%s', but got '%s'", "theme", theme.Name())
+	}
+	if theme.Options().Path != "/app" {
+		t.Errorf("Expected path '%s', but got '%s'", "/app", theme.Options().Path)
+	}
+
+	w := httptest.NewRecorder()
+	if err := theme.Remove(w); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != "theme" || cookie.MaxAge != -1 || cookie.Path != "/app" {
+		t.Errorf("Unexpected cookie: %v", cookie)
+	}
+}
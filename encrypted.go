@@ -0,0 +1,80 @@
+package cookie
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+)
+
+// encrypt seals the given plaintext with AES-GCM using the provided key and a
+// random nonce. The additional data is authenticated but not encrypted.
+func encrypt(plaintext, additionalData, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// decrypt opens ciphertext sealed by encrypt using the provided key.
+func decrypt(ciphertext, additionalData, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidEncryptedCookie
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, additionalData)
+	if err != nil {
+		return nil, ErrInvalidEncryptedCookie
+	}
+	return plaintext, nil
+}
+
+// newGCM creates an AES-GCM AEAD for the given key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptCookieValue encrypts a cookie value, binding it to the cookie name so
+// it cannot be replayed under a different name.
+func encryptCookieValue(name, value string, key []byte) (string, error) {
+	ciphertext, err := encrypt([]byte(value), []byte(name), key)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(ciphertext), nil
+}
+
+// decryptCookieValue decrypts a cookie value encrypted by encryptCookieValue.
+func decryptCookieValue(name, value string, key []byte) (string, error) {
+	ciphertext, err := base64.URLEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := decrypt(ciphertext, []byte(name), key)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// CanEncrypt reports whether the Manager has a key to encrypt cookies with.
+func (m *Manager) CanEncrypt() bool {
+	return m.encryptionKey != nil
+}
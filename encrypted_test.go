@@ -0,0 +1,96 @@
+package cookie
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("example data")
+	additionalData := []byte("example name")
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	ciphertext, err := encrypt(plaintext, additionalData, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if bytes.Contains(ciphertext, plaintext) {
+		t.Error("encrypt failed to hide the plaintext")
+	}
+
+	again, err := encrypt(plaintext, additionalData, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bytes.Equal(ciphertext, again) {
+		t.Error("encrypt reused a nonce")
+	}
+
+	decrypted, err := decrypt(ciphertext, additionalData, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Expected plaintext '%s', but got '%s'", plaintext, decrypted)
+	}
+}
+
+func TestDecrypt_Errors(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	ciphertext, err := encrypt([]byte("example data"), nil, key)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tampered := append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 0xff
+
+	tests := map[string]struct {
+		ciphertext []byte
+		key        []byte
+	}{
+		"short":     {ciphertext: []byte("short"), key: key},
+		"tampered":  {ciphertext: tampered, key: key},
+		"wrong key": {ciphertext: ciphertext, key: []byte("fedcba9876543210fedcba9876543210")},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decrypt(tt.ciphertext, nil, tt.key)
+			if err != ErrInvalidEncryptedCookie {
+				t.Errorf("Expected error '%v', but got '%v'", ErrInvalidEncryptedCookie, err)
+			}
+		})
+	}
+}
+
+func TestEncrypt_InvalidKey(t *testing.T) {
+	_, err := encrypt([]byte("example data"), nil, []byte("invalid"))
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+
+	_, err = decrypt([]byte("example data"), nil, []byte("invalid"))
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func TestDecryptCookieValue_Base64Error(t *testing.T) {
+	_, err := decryptCookieValue("name", "invalid base64!", []byte("0123456789abcdef0123456789abcdef"))
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func TestManager_CanEncrypt(t *testing.T) {
+	if unsignedManager.CanEncrypt() {
+		t.Error("Expected manager without an encryption key not to encrypt")
+	}
+	if !NewManager(WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef"))).CanEncrypt() {
+		t.Error("Expected manager with an encryption key to encrypt")
+	}
+}
@@ -3,6 +3,8 @@ package cookie
 import (
 	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 // ErrInvalidSignedCookieFormat is returned when the format of a signed cookie is invalid.
@@ -11,9 +13,46 @@ var ErrInvalidSignedCookieFormat = errors.New("invalid signed cookie format")
 // ErrInvalidCookieSignature is returned when the signature of a signed cookie is invalid.
 var ErrInvalidCookieSignature = errors.New("invalid cookie signature")
 
+// ErrSignatureExpired is returned when a signed cookie is older than the allowed max age.
+var ErrSignatureExpired = errors.New("cookie signature expired")
+
+// ErrUnknownSigningKey is returned when a signed cookie references a key ID that is not in the keyring.
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// ErrSigningKeyRequired is returned when signing is required but the Manager has no signing key.
+var ErrSigningKeyRequired = errors.New("signing key required")
+
+// ErrFlashTooLarge is returned when adding a flash would exceed the maximum size of the flash cookie.
+var ErrFlashTooLarge = errors.New("flash cookie too large")
+
+// ErrSigningNotSupported is returned when signing with a Signer that can only verify signatures.
+var ErrSigningNotSupported = errors.New("signer cannot sign, only verify")
+
+// ErrEncryptionKeyRequired is returned when encrypting or decrypting a cookie without an encryption key.
+var ErrEncryptionKeyRequired = errors.New("encryption key required")
+
+// ErrInvalidEncryptedCookie is returned when an encrypted cookie cannot be decrypted.
+var ErrInvalidEncryptedCookie = errors.New("invalid encrypted cookie")
+
+// ErrInvalidCompressedCookie is returned when a compressed cookie cannot be decompressed.
+var ErrInvalidCompressedCookie = errors.New("invalid compressed cookie")
+
+// ErrTooManyChunks is returned when a cookie value would be split into more chunks than allowed.
+var ErrTooManyChunks = errors.New("cookie value requires too many chunks")
+
+// ErrInvalidChunkedCookie is returned when the chunks of a cookie are missing or do not match their checksum.
+var ErrInvalidChunkedCookie = errors.New("invalid chunked cookie")
+
 // ErrNonNilPointerRequired is returned when the destination parameter must be a non-nil pointer.
 var ErrNonNilPointerRequired = errors.New("dest must be a non-nil pointer")
 
+// ErrInvalidMapEntry is returned when a map entry is not in the key=value format.
+var ErrInvalidMapEntry = errors.New("invalid map entry, expected key=value")
+
+// ErrAmbiguousValue is returned when writing a slice or map whose elements contain the separator, map keys containing '=',
+// or a slice holding a single empty element, which would not read back unchanged.
+var ErrAmbiguousValue = errors.New("slice or map value would not read back unchanged")
+
 // ErrUnsupportedType is returned when a field type is not supported.
 type ErrUnsupportedType struct {
 	Type reflect.Type
@@ -23,3 +62,113 @@ type ErrUnsupportedType struct {
 func (e *ErrUnsupportedType) Error() string {
 	return "cookie: unsupported type: " + e.Type.String()
 }
+
+// ValidationError is returned when a populated struct field fails a
+// validation rule of its tag.
+type ValidationError struct {
+	// Rule is the failed rule as written in the tag, such as "min=1".
+	Rule string
+
+	// Err is the error returned by a validator registered with WithValidator,
+	// if any.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	msg := "cookie: failed validation " + strconv.Quote(e.Rule)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the error returned by the validator, if any.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownRuleError is returned when a `cookie` struct tag has an option that is
+// neither a known option, a built-in validation rule nor a rule registered with
+// WithValidator, such as a misspelled option.
+type UnknownRuleError struct {
+	// Field is the path of the field from the outermost struct, such as
+	// "Prefs.Theme".
+	Field string
+
+	// Rule is the unknown rule as written in the tag, such as "requird".
+	Rule string
+}
+
+// Error returns the error message.
+func (e *UnknownRuleError) Error() string {
+	return "cookie: field " + e.Field + ": unknown tag option or rule " + strconv.Quote(e.Rule)
+}
+
+// PrefixError is returned when the attributes of a cookie break the rules of
+// its SecurePrefix or HostPrefix name prefix, so browsers would reject it.
+type PrefixError struct {
+	// Cookie is the name of the cookie.
+	Cookie string
+
+	// Prefix is the prefix of the name whose rules are broken.
+	Prefix string
+
+	// Reason describes the broken rule, such as "must be Secure".
+	Reason string
+}
+
+// Error returns the error message.
+func (e *PrefixError) Error() string {
+	return "cookie: " + e.Prefix + " cookie " + strconv.Quote(e.Cookie) + " " + e.Reason
+}
+
+// FieldError records the failure to populate a single struct field.
+type FieldError struct {
+	// Field is the path of the field from the outermost struct, such as
+	// "Prefs.Theme".
+	Field string
+
+	// Cookie is the name of the cookie the field is populated from.
+	Cookie string
+
+	// Value is the value of the cookie, after verification or decryption when
+	// those succeeded, or empty when the cookie is missing.
+	Value string
+
+	// Err is the cause of the failure.
+	Err error
+}
+
+// Error returns the error message.
+func (e *FieldError) Error() string {
+	return "cookie: field " + e.Field + " (cookie " + strconv.Quote(e.Cookie) + "): " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors is returned by PopulateFromCookies when one or more fields could
+// not be populated. It works with errors.Is and errors.As, which match against
+// each FieldError and its cause.
+type FieldErrors []*FieldError
+
+// Error returns the error message.
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns each FieldError.
+func (e FieldErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
@@ -1,6 +1,8 @@
 package cookie
 
 import (
+	"errors"
+	"net/http"
 	"reflect"
 	"testing"
 )
@@ -13,3 +15,44 @@ func TestErrUnsupportedType_Error(t *testing.T) {
 		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
 	}
 }
+
+func TestFieldErrors_Error(t *testing.T) {
+	err := FieldErrors{
+		{Field: "Page", Cookie: "page", Value: "abc", Err: errors.New("invalid syntax")},
+		{Field: "Token", Cookie: "token", Err: http.ErrNoCookie},
+	}
+	expected := `cookie: field Page (cookie "page"): invalid syntax; cookie: field Token (cookie "token"): http: named cookie not present`
+
+	if err.Error() != expected {
+		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
+	}
+
+	if !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("Expected error to match http.ErrNoCookie")
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	err := &ValidationError{Rule: "min=1"}
+	expected := `cookie: failed validation "min=1"`
+
+	if err.Error() != expected {
+		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
+	}
+
+	err = &ValidationError{Rule: "locale", Err: errors.New("unknown locale")}
+	expected = `cookie: failed validation "locale": unknown locale`
+
+	if err.Error() != expected {
+		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
+	}
+}
+
+func TestUnknownRuleError_Error(t *testing.T) {
+	err := &UnknownRuleError{Field: "Prefs.Theme", Rule: "requird"}
+	expected := `cookie: field Prefs.Theme: unknown tag option or rule "requird"`
+
+	if err.Error() != expected {
+		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
+	}
+}
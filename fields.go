@@ -0,0 +1,124 @@
+package cookie
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+)
+
+// fieldFunc is called for each tagged field of a struct, with the path of the
+// field from the outermost struct, such as "Prefs.Theme", the options of its
+// tag and the cookie name resolved against any prefix.
+type fieldFunc func(path string, fieldVal reflect.Value, opts tagOptions) error
+
+// fieldSpec describes a tagged field of a struct, as compiled by
+// compileFields.
+type fieldSpec struct {
+	path  string
+	index []int
+	opts  tagOptions
+}
+
+// fieldPlan is the cached result of compileFields for a struct type.
+type fieldPlan struct {
+	fields []fieldSpec
+	err    error
+}
+
+// walkFields calls fn for each tagged field of the struct v, in the order
+// given by the field plan of its type. Plans are compiled once per type and
+// cached on the Manager, so struct tags are only parsed on first use.
+func (m *Manager) walkFields(v reflect.Value, fn fieldFunc) error {
+	t := v.Type()
+	cached, ok := m.fieldPlans.Load(t)
+	if !ok {
+		fields, err := m.compileFields(t, "", nil, tagOptions{})
+		cached, _ = m.fieldPlans.LoadOrStore(t, &fieldPlan{fields: fields, err: err})
+	}
+
+	plan := cached.(*fieldPlan)
+	if plan.err != nil {
+		return plan.err
+	}
+	for _, field := range plan.fields {
+		if err := fn(field.path, v.FieldByIndex(field.index), field.opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileFields returns the tagged fields of the struct type t. Anonymous
+// embedded structs are flattened, while fields tagged inline are compiled with
+// their tag name as a prefix for the names of their own fields, which inherit
+// the signed, encrypted and omitempty options of the inline tag. The parent
+// path and index are prepended to those of the fields. Validation rules that
+// are neither built in nor registered with WithValidator are reported as an
+// UnknownRuleError.
+func (m *Manager) compileFields(t reflect.Type, parentPath string, parentIndex []int, parent tagOptions) ([]fieldSpec, error) {
+	var fields []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		path := field.Name
+		if parentPath != "" {
+			path = parentPath + "." + field.Name
+		}
+		index := append(append([]int(nil), parentIndex...), i)
+
+		tag := field.Tag.Get("cookie")
+		if tag == "" {
+			if field.Anonymous && isNestedStruct(field.Type) {
+				nested, err := m.compileFields(field.Type, path, index, parent)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, nested...)
+			}
+			continue
+		}
+
+		opts := parseTag(tag)
+		opts.name = parent.name + opts.name
+		opts.signed = opts.signed || parent.signed
+		opts.unsigned = opts.unsigned || (parent.unsigned && !opts.signed)
+		opts.encrypted = opts.encrypted || parent.encrypted
+		opts.omitempty = opts.omitempty || parent.omitempty
+
+		if opts.inline {
+			if !isNestedStruct(field.Type) {
+				return nil, &ErrUnsupportedType{Type: field.Type}
+			}
+			nested, err := m.compileFields(field.Type, path, index, opts)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+
+		for j, rule := range opts.rules {
+			switch {
+			case rule.name == "pattern":
+				re, err := regexp.Compile(rule.param)
+				if err != nil {
+					return nil, err
+				}
+				opts.rules[j].re = re
+			case isBuiltinRule(rule.name):
+			case m.validators[rule.name] != nil:
+			default:
+				return nil, &UnknownRuleError{Field: path, Rule: rule.String()}
+			}
+		}
+
+		fields = append(fields, fieldSpec{path: path, index: index, opts: opts})
+	}
+	return fields, nil
+}
+
+// isNestedStruct reports whether t is a struct whose fields can be walked, as
+// opposed to a struct handled as a single value such as time.Time.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{})
+}
@@ -0,0 +1,172 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type walkedBase struct {
+	ID   int    `cookie:"id"`
+	Name string `cookie:"name"`
+}
+
+type walkedPrefs struct {
+	Theme string `cookie:"theme"`
+	Lang  string `cookie:"lang,omitempty"`
+}
+
+func TestWalkFields(t *testing.T) {
+	type MyStruct struct {
+		walkedBase
+		Prefs     walkedPrefs `cookie:"prefs_,inline"`
+		Secure    walkedPrefs `cookie:"secure_,inline,signed"`
+		Timestamp time.Time   `cookie:"ts"`
+		Untagged  walkedPrefs
+	}
+
+	var paths []string
+	var visited []tagOptions
+	err := unsignedManager.walkFields(reflect.ValueOf(MyStruct{}), func(path string, fieldVal reflect.Value, opts tagOptions) error {
+		paths = append(paths, path)
+		visited = append(visited, opts)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []tagOptions{
+		{name: "id"},
+		{name: "name"},
+		{name: "prefs_theme"},
+		{name: "prefs_lang", omitempty: true},
+		{name: "secure_theme", signed: true},
+		{name: "secure_lang", signed: true, omitempty: true},
+		{name: "ts"},
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("Unexpected result. Got: %+v, want: %+v", visited, expected)
+	}
+
+	expectedPaths := []string{
+		"walkedBase.ID",
+		"walkedBase.Name",
+		"Prefs.Theme",
+		"Prefs.Lang",
+		"Secure.Theme",
+		"Secure.Lang",
+		"Timestamp",
+	}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Unexpected paths. Got: %v, want: %v", paths, expectedPaths)
+	}
+}
+
+func TestWalkFields_InlineUnsupportedType(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"prefix_,inline"`
+	}
+
+	err := unsignedManager.walkFields(reflect.ValueOf(MyStruct{}), func(string, reflect.Value, tagOptions) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected error, but got nil")
+	}
+
+	expectedError := "cookie: unsupported type: string"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
+	}
+}
+
+func TestWalkFields_CachesPlan(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"field"`
+	}
+
+	manager := NewManager()
+	walk := func() {
+		err := manager.walkFields(reflect.ValueOf(MyStruct{}), func(string, reflect.Value, tagOptions) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	walk()
+	first, ok := manager.fieldPlans.Load(reflect.TypeOf(MyStruct{}))
+	if !ok {
+		t.Fatal("Expected field plan to be cached")
+	}
+
+	walk()
+	second, _ := manager.fieldPlans.Load(reflect.TypeOf(MyStruct{}))
+	if first != second {
+		t.Error("Expected cached field plan to be reused")
+	}
+}
+
+func TestWalkFields_InvalidPattern(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"field,pattern=["`
+	}
+
+	for i := 0; i < 2; i++ {
+		err := unsignedManager.walkFields(reflect.ValueOf(MyStruct{}), func(string, reflect.Value, tagOptions) error {
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Expected error, but got nil")
+		}
+	}
+}
+
+type benchmarkCookies struct {
+	Theme    string      `cookie:"theme,oneof=light dark"`
+	PageSize int         `cookie:"page_size,min=10,max=100"`
+	Debug    bool        `cookie:"debug,omitempty"`
+	Tags     []string    `cookie:"tags"`
+	Since    time.Time   `cookie:"since"`
+	Prefs    walkedPrefs `cookie:"prefs_,inline"`
+}
+
+func newBenchmarkRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "page_size", Value: "50"})
+	req.AddCookie(&http.Cookie{Name: "tags", Value: "a,b,c"})
+	req.AddCookie(&http.Cookie{Name: "since", Value: "2021-01-02T15:04:05Z"})
+	req.AddCookie(&http.Cookie{Name: "prefs_theme", Value: "dark"})
+	req.AddCookie(&http.Cookie{Name: "prefs_lang", Value: "en"})
+	return req
+}
+
+func BenchmarkPopulateFromCookies_Cold(b *testing.B) {
+	manager := NewManager()
+	req := newBenchmarkRequest()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		manager.fieldPlans.Clear()
+		if err := manager.PopulateFromCookies(req, &benchmarkCookies{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPopulateFromCookies_Warm(b *testing.B) {
+	manager := NewManager()
+	req := newBenchmarkRequest()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := manager.PopulateFromCookies(req, &benchmarkCookies{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
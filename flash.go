@@ -0,0 +1,181 @@
+package cookie
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// DefaultFlashCookieName is the default name of the cookie storing flashes.
+const DefaultFlashCookieName = "_flash"
+
+// DefaultFlashMaxSize is the default maximum size in bytes of the name and
+// value of the cookie storing flashes.
+const DefaultFlashMaxSize = 4096
+
+// FlashKind is the category of a flash message.
+type FlashKind string
+
+// Flash message categories.
+const (
+	FlashInfo  FlashKind = "info"
+	FlashWarn  FlashKind = "warn"
+	FlashError FlashKind = "error"
+)
+
+// Flash is a one-time message stored in a cookie, typically shown to the user
+// after a redirect.
+type Flash struct {
+	Kind    FlashKind `json:"kind"`
+	Message string    `json:"message"`
+}
+
+// flashOptions are the options of the cookie storing flashes.
+var flashOptions = Options{
+	Path:     "/",
+	HttpOnly: true,
+	SameSite: http.SameSiteLaxMode,
+	Signed:   true,
+}
+
+// WithFlashCookieName sets the name of the cookie storing flashes.
+func WithFlashCookieName(name string) Option {
+	return func(m *Manager) {
+		m.flashCookieName = name
+	}
+}
+
+// WithFlashMaxSize sets the maximum size in bytes of the name and value of the
+// cookie storing flashes. AddFlash returns ErrFlashTooLarge rather than exceed
+// it.
+func WithFlashMaxSize(size int) Option {
+	return func(m *Manager) {
+		m.flashMaxSize = size
+	}
+}
+
+// AddFlash adds a flash message to be read by a later request. Flashes are
+// stored in a signed cookie, so the Manager must have a signing key. Flashes
+// added earlier to the same response are kept, while those of the request are
+// kept only if Flashes has not removed them in the same response.
+func (m *Manager) AddFlash(w http.ResponseWriter, r *http.Request, kind FlashKind, message string) error {
+	if !m.CanSign() {
+		return ErrSigningKeyRequired
+	}
+
+	flashes, ok := m.pendingFlashes(w)
+	if !ok {
+		flashes, _ = m.readFlashes(r)
+	}
+	flashes = append(flashes, Flash{Kind: kind, Message: message})
+
+	data, err := json.Marshal(flashes)
+	if err != nil {
+		return err
+	}
+	value, err := m.Encode(m.flashCookieName, string(data), flashOptions)
+	if err != nil {
+		return err
+	}
+	if len(m.flashCookieName)+len(value) > m.flashMaxSize {
+		return ErrFlashTooLarge
+	}
+
+	m.removeSetCookie(w, m.flashCookieName)
+	return m.SetEncoded(w, m.flashCookieName, value, flashOptions)
+}
+
+// Flashes returns the flash messages of the request, and removes them so they
+// are only read once. It returns no flashes when there are none, and an error
+// when the cookie storing them is invalid, in which case it is also removed.
+func (m *Manager) Flashes(w http.ResponseWriter, r *http.Request) ([]Flash, error) {
+	if _, err := r.Cookie(m.flashCookieName); err != nil {
+		return nil, nil
+	}
+
+	flashes, err := m.readFlashes(r)
+	if removeErr := m.Remove(w, m.flashCookieName, flashOptions); removeErr != nil {
+		return nil, removeErr
+	}
+	return flashes, err
+}
+
+// readFlashes reads the flashes stored in the cookie of the request.
+func (m *Manager) readFlashes(r *http.Request) ([]Flash, error) {
+	value, err := m.readCookie(r, m.flashCookieName)
+	if err != nil {
+		return nil, err
+	}
+	return m.decodeFlashes(value)
+}
+
+// decodeFlashes verifies and decodes the value of the cookie storing flashes.
+func (m *Manager) decodeFlashes(value string) ([]Flash, error) {
+	data, err := m.verifyValue(m.flashCookieName, value, m.maxAge)
+	if err != nil {
+		return nil, err
+	}
+	data, err = m.decompress(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var flashes []Flash
+	if err := json.Unmarshal([]byte(data), &flashes); err != nil {
+		return nil, err
+	}
+	return flashes, nil
+}
+
+// pendingFlashes returns the flashes already added to the response, and
+// whether the response sets the cookie storing them at all. A cookie removed
+// by Flashes holds no flashes.
+func (m *Manager) pendingFlashes(w http.ResponseWriter) ([]Flash, bool) {
+	values := make(map[string]string)
+	var removed bool
+	for _, line := range w.Header().Values("Set-Cookie") {
+		if c, err := http.ParseSetCookie(line); err == nil {
+			values[c.Name] = c.Value
+			if c.Name == m.flashCookieName {
+				removed = c.MaxAge < 0
+			}
+		}
+	}
+
+	value, ok := values[m.flashCookieName]
+	if !ok {
+		return nil, false
+	}
+	if removed || value == "" {
+		return nil, true
+	}
+	value, err := m.joinChunks(m.flashCookieName, value, func(name string) (string, bool) {
+		chunk, ok := values[name]
+		return chunk, ok
+	})
+	if err != nil {
+		return nil, true
+	}
+	flashes, err := m.decodeFlashes(value)
+	if err != nil {
+		return nil, true
+	}
+	return flashes, true
+}
+
+// removeSetCookie removes any Set-Cookie header for the named cookie, or for
+// the chunks of its value, from the response.
+func (m *Manager) removeSetCookie(w http.ResponseWriter, name string) {
+	lines := w.Header().Values("Set-Cookie")
+	kept := lines[:0:0]
+	for _, line := range lines {
+		if c, err := http.ParseSetCookie(line); err == nil && (c.Name == name || m.isChunkName(c.Name, name)) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) == 0 {
+		w.Header().Del("Set-Cookie")
+		return
+	}
+	w.Header()["Set-Cookie"] = kept
+}
@@ -0,0 +1,186 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestManager_AddFlash(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	if err := signedManager.AddFlash(w, r, FlashInfo, "Saved"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := signedManager.AddFlash(w, r, FlashWarn, "Almost full"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, but got %d", len(cookies))
+	}
+	if cookies[0].Name != DefaultFlashCookieName {
+		t.Errorf("Expected cookie name '%s', but got '%s'", DefaultFlashCookieName, cookies[0].Name)
+	}
+
+	// A later request adds another flash to those not read yet.
+	r = httptest.NewRequest(http.MethodPost, "/", nil)
+	r.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	if err := signedManager.AddFlash(w, r, FlashError, "Failed"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+	w = httptest.NewRecorder()
+
+	flashes, err := signedManager.Flashes(w, r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Flash{
+		{Kind: FlashInfo, Message: "Saved"},
+		{Kind: FlashWarn, Message: "Almost full"},
+		{Kind: FlashError, Message: "Failed"},
+	}
+	if !reflect.DeepEqual(flashes, expected) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", flashes, expected)
+	}
+
+	cookies = w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultFlashCookieName || cookies[0].MaxAge != -1 {
+		t.Errorf("Expected flash cookie to be removed, but got %v", cookies)
+	}
+}
+
+func TestManager_AddFlash_KeepsOtherCookies(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	signedManager.Set(w, "other", "value")
+	signedManager.AddFlash(w, r, FlashInfo, "first")
+	signedManager.AddFlash(w, r, FlashInfo, "second")
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 || cookies[0].Name != "other" {
+		t.Errorf("Expected other cookie and flash cookie, but got %v", cookies)
+	}
+}
+
+func TestManager_AddFlash_AfterFlashes(t *testing.T) {
+	w := httptest.NewRecorder()
+	signedManager.AddFlash(w, httptest.NewRequest(http.MethodPost, "/", nil), FlashInfo, "Saved")
+
+	// Flashes read in a response are not added back by a later AddFlash.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+	w = httptest.NewRecorder()
+	if _, err := signedManager.Flashes(w, r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := signedManager.AddFlash(w, r, FlashWarn, "Almost full"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, but got %d", len(cookies))
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+	flashes, err := signedManager.Flashes(httptest.NewRecorder(), r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Flash{{Kind: FlashWarn, Message: "Almost full"}}
+	if !reflect.DeepEqual(flashes, expected) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", flashes, expected)
+	}
+}
+
+func TestManager_AddFlash_NoSigningKey(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	err := unsignedManager.AddFlash(w, r, FlashInfo, "Saved")
+	if err != ErrSigningKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningKeyRequired, err)
+	}
+}
+
+func TestManager_AddFlash_TooLarge(t *testing.T) {
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithFlashCookieName("flash"),
+		WithFlashMaxSize(128),
+	)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	if err := manager.AddFlash(w, r, FlashInfo, "short"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := manager.AddFlash(w, r, FlashInfo, strings.Repeat("long", 32))
+	if err != ErrFlashTooLarge {
+		t.Errorf("Expected error '%v', but got '%v'", ErrFlashTooLarge, err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "flash" {
+		t.Fatalf("Expected the earlier flash cookie to be kept, but got %v", cookies)
+	}
+}
+
+func TestManager_Flashes_None(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	flashes, err := signedManager.Flashes(w, r)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if flashes != nil {
+		t.Errorf("Expected no flashes, but got %v", flashes)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be written")
+	}
+}
+
+func TestManager_Flashes_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"forged":       "forged",
+		"invalid json": signCookieValue("invalid", signedManager.signingKey),
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(&http.Cookie{Name: DefaultFlashCookieName, Value: value})
+
+			flashes, err := signedManager.Flashes(w, r)
+			if err == nil {
+				t.Error("Expected error, but got nil")
+			}
+			if flashes != nil {
+				t.Errorf("Expected no flashes, but got %v", flashes)
+			}
+
+			cookies := w.Result().Cookies()
+			if len(cookies) != 1 || cookies[0].MaxAge != -1 {
+				t.Errorf("Expected invalid flash cookie to be removed, but got %v", cookies)
+			}
+		})
+	}
+}
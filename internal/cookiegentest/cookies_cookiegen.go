@@ -0,0 +1,1104 @@
+// Code generated by cookiegen. DO NOT EDIT.
+
+package cookiegentest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syntaqx/cookie"
+)
+
+// PopulateFromCookies populates s with cookie values. It behaves like
+// Manager.PopulateFromCookies, without using reflection.
+func (s *Cookies) PopulateFromCookies(m *cookie.Manager, r *http.Request) error {
+	if s == nil {
+		return cookie.ErrNonNilPointerRequired
+	}
+
+	var errs cookie.FieldErrors
+
+	// Base.UserID
+	{
+		value, err := m.GetSigned(r, "User-ID")
+		if err != nil {
+			if c, cookieErr := r.Cookie("User-ID"); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &cookie.FieldError{Field: "Base.UserID", Cookie: "User-ID", Value: value, Err: err})
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 0); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Base.UserID", Cookie: "User-ID", Value: value, Err: err})
+			} else {
+				s.Base.UserID = int(v)
+			}
+		}
+	}
+
+	// Base.Token
+	{
+		value, err := m.GetEncrypted(r, "Token")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("Token"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Base.Token", Cookie: "Token", Value: value, Err: err})
+			}
+		} else {
+			s.Base.Token = value
+		}
+	}
+
+	// Name
+	{
+		value, err := m.Get(r, "name")
+		if err != nil {
+			if c, cookieErr := r.Cookie("name"); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &cookie.FieldError{Field: "Name", Cookie: "name", Value: value, Err: err})
+		} else {
+			s.Name = value
+		}
+	}
+
+	// Enabled
+	{
+		value, err := m.Get(r, "enabled")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("enabled"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Enabled", Cookie: "enabled", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseBool(value); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Enabled", Cookie: "enabled", Value: value, Err: err})
+			} else {
+				s.Enabled = v
+			}
+		}
+	}
+
+	// Int
+	{
+		value, err := m.Get(r, "int")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("int"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Int", Cookie: "int", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 0); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Int", Cookie: "int", Value: value, Err: err})
+			} else {
+				s.Int = int(v)
+			}
+		}
+	}
+
+	// Int8
+	{
+		value, err := m.Get(r, "int8")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("int8"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Int8", Cookie: "int8", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 8); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Int8", Cookie: "int8", Value: value, Err: err})
+			} else {
+				s.Int8 = int8(v)
+			}
+		}
+	}
+
+	// Int16
+	{
+		value, err := m.Get(r, "int16")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("int16"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Int16", Cookie: "int16", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 16); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Int16", Cookie: "int16", Value: value, Err: err})
+			} else {
+				s.Int16 = int16(v)
+			}
+		}
+	}
+
+	// Int32
+	{
+		value, err := m.Get(r, "int32")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("int32"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Int32", Cookie: "int32", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 32); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Int32", Cookie: "int32", Value: value, Err: err})
+			} else {
+				s.Int32 = int32(v)
+			}
+		}
+	}
+
+	// Int64
+	{
+		value, err := m.Get(r, "int64")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("int64"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Int64", Cookie: "int64", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 64); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Int64", Cookie: "int64", Value: value, Err: err})
+			} else {
+				s.Int64 = int64(v)
+			}
+		}
+	}
+
+	// Uint
+	{
+		value, err := m.Get(r, "uint")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("uint"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Uint", Cookie: "uint", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseUint(value, 10, 0); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Uint", Cookie: "uint", Value: value, Err: err})
+			} else {
+				s.Uint = uint(v)
+			}
+		}
+	}
+
+	// Uint8
+	{
+		value, err := m.Get(r, "uint8")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("uint8"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Uint8", Cookie: "uint8", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseUint(value, 10, 8); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Uint8", Cookie: "uint8", Value: value, Err: err})
+			} else {
+				s.Uint8 = uint8(v)
+			}
+		}
+	}
+
+	// Uint16
+	{
+		value, err := m.Get(r, "uint16")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("uint16"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Uint16", Cookie: "uint16", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseUint(value, 10, 16); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Uint16", Cookie: "uint16", Value: value, Err: err})
+			} else {
+				s.Uint16 = uint16(v)
+			}
+		}
+	}
+
+	// Uint32
+	{
+		value, err := m.Get(r, "uint32")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("uint32"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Uint32", Cookie: "uint32", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseUint(value, 10, 32); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Uint32", Cookie: "uint32", Value: value, Err: err})
+			} else {
+				s.Uint32 = uint32(v)
+			}
+		}
+	}
+
+	// Uint64
+	{
+		value, err := m.Get(r, "uint64")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("uint64"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Uint64", Cookie: "uint64", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseUint(value, 10, 64); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Uint64", Cookie: "uint64", Value: value, Err: err})
+			} else {
+				s.Uint64 = uint64(v)
+			}
+		}
+	}
+
+	// Float32
+	{
+		value, err := m.Get(r, "float32")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("float32"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Float32", Cookie: "float32", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseFloat(value, 32); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Float32", Cookie: "float32", Value: value, Err: err})
+			} else {
+				s.Float32 = float32(v)
+			}
+		}
+	}
+
+	// Float64
+	{
+		value, err := m.Get(r, "float64")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("float64"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Float64", Cookie: "float64", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseFloat(value, 64); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Float64", Cookie: "float64", Value: value, Err: err})
+			} else {
+				s.Float64 = v
+			}
+		}
+	}
+
+	// Since
+	{
+		value, err := m.Get(r, "since")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("since"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Since", Cookie: "since", Value: value, Err: err})
+			}
+		} else {
+			if v, err := time.Parse(time.RFC3339, value); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Since", Cookie: "since", Value: value, Err: err})
+			} else {
+				s.Since = v
+			}
+		}
+	}
+
+	// Page
+	{
+		value, err := m.Get(r, "page")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("page"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Page", Cookie: "page", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 0); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Page", Cookie: "page", Value: value, Err: err})
+			} else {
+				p := int(v)
+				s.Page = &p
+			}
+		}
+	}
+
+	// Nickname
+	{
+		value, err := m.Get(r, "nickname")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("nickname"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Nickname", Cookie: "nickname", Value: value, Err: err})
+			}
+		} else {
+			v := value
+			s.Nickname = &v
+		}
+	}
+
+	// Seen
+	{
+		value, err := m.Get(r, "seen")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("seen"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Seen", Cookie: "seen", Value: value, Err: err})
+			}
+		} else {
+			if v, err := time.Parse(time.RFC3339, value); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Seen", Cookie: "seen", Value: value, Err: err})
+			} else {
+				p := v
+				s.Seen = &p
+			}
+		}
+	}
+
+	// IDs
+	{
+		value, err := m.Get(r, "ids")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("ids"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "IDs", Cookie: "ids", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.IDs = []int{}
+			} else {
+				parts := strings.Split(value, ",")
+				slice := make([]int, len(parts))
+				var err error
+				for i, part := range parts {
+					v, parseErr := strconv.Atoi(part)
+					if parseErr != nil {
+						err = parseErr
+						break
+					}
+					slice[i] = v
+				}
+				if err != nil {
+					errs = append(errs, &cookie.FieldError{Field: "IDs", Cookie: "ids", Value: value, Err: err})
+				} else {
+					s.IDs = slice
+				}
+			}
+		}
+	}
+
+	// Sizes
+	{
+		value, err := m.Get(r, "sizes")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("sizes"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Sizes", Cookie: "sizes", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.Sizes = []int64{}
+			} else {
+				parts := strings.Split(value, ":")
+				slice := make([]int64, len(parts))
+				var err error
+				for i, part := range parts {
+					v, parseErr := strconv.ParseInt(part, 10, 64)
+					if parseErr != nil {
+						err = parseErr
+						break
+					}
+					slice[i] = int64(v)
+				}
+				if err != nil {
+					errs = append(errs, &cookie.FieldError{Field: "Sizes", Cookie: "sizes", Value: value, Err: err})
+				} else {
+					s.Sizes = slice
+				}
+			}
+		}
+	}
+
+	// Ratios
+	{
+		value, err := m.Get(r, "ratios")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("ratios"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Ratios", Cookie: "ratios", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.Ratios = []float64{}
+			} else {
+				parts := strings.Split(value, ",")
+				slice := make([]float64, len(parts))
+				var err error
+				for i, part := range parts {
+					v, parseErr := strconv.ParseFloat(part, 64)
+					if parseErr != nil {
+						err = parseErr
+						break
+					}
+					slice[i] = v
+				}
+				if err != nil {
+					errs = append(errs, &cookie.FieldError{Field: "Ratios", Cookie: "ratios", Value: value, Err: err})
+				} else {
+					s.Ratios = slice
+				}
+			}
+		}
+	}
+
+	// Times
+	{
+		value, err := m.Get(r, "times")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("times"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Times", Cookie: "times", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.Times = []time.Time{}
+			} else {
+				parts := strings.Split(value, ",")
+				slice := make([]time.Time, len(parts))
+				var err error
+				for i, part := range parts {
+					v, parseErr := time.Parse(time.RFC3339, part)
+					if parseErr != nil {
+						err = parseErr
+						break
+					}
+					slice[i] = v
+				}
+				if err != nil {
+					errs = append(errs, &cookie.FieldError{Field: "Times", Cookie: "times", Value: value, Err: err})
+				} else {
+					s.Times = slice
+				}
+			}
+		}
+	}
+
+	// PageSize
+	{
+		value, err := m.Get(r, "page_size")
+		if err == http.ErrNoCookie {
+			value, err = "20", nil
+		}
+		if err != nil {
+			if c, cookieErr := r.Cookie("page_size"); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &cookie.FieldError{Field: "PageSize", Cookie: "page_size", Value: value, Err: err})
+		} else {
+			if v, err := strconv.ParseInt(value, 10, 0); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "PageSize", Cookie: "page_size", Value: value, Err: err})
+			} else {
+				s.PageSize = int(v)
+			}
+		}
+	}
+
+	// Prefs.Theme
+	{
+		value, err := m.Get(r, "prefs_theme")
+		if err == http.ErrNoCookie {
+			value, err = "light", nil
+		}
+		if err != nil {
+			if c, cookieErr := r.Cookie("prefs_theme"); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &cookie.FieldError{Field: "Prefs.Theme", Cookie: "prefs_theme", Value: value, Err: err})
+		} else {
+			s.Prefs.Theme = value
+		}
+	}
+
+	// Prefs.Tags
+	{
+		value, err := m.Get(r, "prefs_tags")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("prefs_tags"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Prefs.Tags", Cookie: "prefs_tags", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.Prefs.Tags = []string{}
+			} else {
+				s.Prefs.Tags = strings.Split(value, "|")
+			}
+		}
+	}
+
+	// Prefs.Debug
+	{
+		value, err := m.Get(r, "prefs_debug")
+		if err != nil {
+			if c, cookieErr := r.Cookie("prefs_debug"); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &cookie.FieldError{Field: "Prefs.Debug", Cookie: "prefs_debug", Value: value, Err: err})
+		} else {
+			if v, err := strconv.ParseBool(value); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Prefs.Debug", Cookie: "prefs_debug", Value: value, Err: err})
+			} else {
+				s.Prefs.Debug = v
+			}
+		}
+	}
+
+	// Secure.Theme
+	{
+		value, err := m.GetSigned(r, "secure_theme")
+		if err == http.ErrNoCookie {
+			value, err = "light", nil
+		}
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("secure_theme"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Secure.Theme", Cookie: "secure_theme", Value: value, Err: err})
+			}
+		} else {
+			s.Secure.Theme = value
+		}
+	}
+
+	// Secure.Tags
+	{
+		value, err := m.GetSigned(r, "secure_tags")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("secure_tags"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Secure.Tags", Cookie: "secure_tags", Value: value, Err: err})
+			}
+		} else {
+			if value == "" {
+				s.Secure.Tags = []string{}
+			} else {
+				s.Secure.Tags = strings.Split(value, "|")
+			}
+		}
+	}
+
+	// Secure.Debug
+	{
+		value, err := m.Get(r, "secure_debug")
+		if err != nil {
+			if err != http.ErrNoCookie {
+				if c, cookieErr := r.Cookie("secure_debug"); cookieErr == nil {
+					value = c.Value
+				}
+				errs = append(errs, &cookie.FieldError{Field: "Secure.Debug", Cookie: "secure_debug", Value: value, Err: err})
+			}
+		} else {
+			if v, err := strconv.ParseBool(value); err != nil {
+				errs = append(errs, &cookie.FieldError{Field: "Secure.Debug", Cookie: "secure_debug", Value: value, Err: err})
+			} else {
+				s.Secure.Debug = v
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// WriteToCookies writes the tagged fields of s as cookies. It behaves like
+// Manager.WriteToCookies, without using reflection.
+func (s *Cookies) WriteToCookies(m *cookie.Manager, w http.ResponseWriter, opts ...cookie.Options) error {
+	if s == nil {
+		return cookie.ErrNonNilPointerRequired
+	}
+
+	var o cookie.Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	// Base.UserID
+	{
+		value := strconv.FormatInt(int64(s.Base.UserID), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = true
+		if err := m.Set(w, "User-ID", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Base.Token
+	if s.Base.Token != "" {
+		value := s.Base.Token
+
+		fieldOpts := o
+		fieldOpts.Encrypted = true
+		fieldOpts.Signed = false
+		if err := m.Set(w, "Token", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Name
+	{
+		value := s.Name
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "name", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Enabled
+	if s.Enabled {
+		value := strconv.FormatBool(s.Enabled)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "enabled", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Int
+	if s.Int != 0 {
+		value := strconv.FormatInt(int64(s.Int), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "int", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Int8
+	if s.Int8 != 0 {
+		value := strconv.FormatInt(int64(s.Int8), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "int8", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Int16
+	if s.Int16 != 0 {
+		value := strconv.FormatInt(int64(s.Int16), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "int16", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Int32
+	if s.Int32 != 0 {
+		value := strconv.FormatInt(int64(s.Int32), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "int32", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Int64
+	if s.Int64 != 0 {
+		value := strconv.FormatInt(int64(s.Int64), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "int64", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Uint
+	if s.Uint != 0 {
+		value := strconv.FormatUint(uint64(s.Uint), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "uint", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Uint8
+	if s.Uint8 != 0 {
+		value := strconv.FormatUint(uint64(s.Uint8), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "uint8", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Uint16
+	if s.Uint16 != 0 {
+		value := strconv.FormatUint(uint64(s.Uint16), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "uint16", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Uint32
+	if s.Uint32 != 0 {
+		value := strconv.FormatUint(uint64(s.Uint32), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "uint32", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Uint64
+	if s.Uint64 != 0 {
+		value := strconv.FormatUint(uint64(s.Uint64), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "uint64", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Float32
+	if s.Float32 != 0 {
+		value := strconv.FormatFloat(float64(s.Float32), 'f', -1, 32)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "float32", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Float64
+	if s.Float64 != 0 {
+		value := strconv.FormatFloat(s.Float64, 'f', -1, 64)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "float64", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Since
+	if s.Since != (time.Time{}) {
+		value := s.Since.Format(time.RFC3339)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "since", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Page
+	if s.Page != nil {
+		value := strconv.FormatInt(int64((*s.Page)), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "page", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Nickname
+	if s.Nickname != nil {
+		value := (*s.Nickname)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "nickname", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Seen
+	if s.Seen != nil {
+		value := (*s.Seen).Format(time.RFC3339)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "seen", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// IDs
+	if s.IDs != nil {
+		parts := make([]string, len(s.IDs))
+		for i, v := range s.IDs {
+			parts[i] = strconv.FormatInt(int64(v), 10)
+			if strings.Contains(parts[i], ",") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, ",")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "ids", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Sizes
+	if s.Sizes != nil {
+		parts := make([]string, len(s.Sizes))
+		for i, v := range s.Sizes {
+			parts[i] = strconv.FormatInt(int64(v), 10)
+			if strings.Contains(parts[i], ":") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, ":")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "sizes", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Ratios
+	if s.Ratios != nil {
+		parts := make([]string, len(s.Ratios))
+		for i, v := range s.Ratios {
+			parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			if strings.Contains(parts[i], ",") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, ",")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "ratios", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Times
+	if s.Times != nil {
+		parts := make([]string, len(s.Times))
+		for i, v := range s.Times {
+			parts[i] = v.Format(time.RFC3339)
+			if strings.Contains(parts[i], ",") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, ",")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "times", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// PageSize
+	{
+		value := strconv.FormatInt(int64(s.PageSize), 10)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "page_size", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Prefs.Theme
+	{
+		value := s.Prefs.Theme
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "prefs_theme", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Prefs.Tags
+	if s.Prefs.Tags != nil {
+		parts := make([]string, len(s.Prefs.Tags))
+		for i, v := range s.Prefs.Tags {
+			parts[i] = v
+			if strings.Contains(parts[i], "|") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, "|")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "prefs_tags", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Prefs.Debug
+	{
+		value := strconv.FormatBool(s.Prefs.Debug)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "prefs_debug", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Secure.Theme
+	if s.Secure.Theme != "" {
+		value := s.Secure.Theme
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = true
+		if err := m.Set(w, "secure_theme", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Secure.Tags
+	if s.Secure.Tags != nil {
+		parts := make([]string, len(s.Secure.Tags))
+		for i, v := range s.Secure.Tags {
+			parts[i] = v
+			if strings.Contains(parts[i], "|") {
+				return cookie.ErrAmbiguousValue
+			}
+		}
+		if len(parts) == 1 && parts[0] == "" {
+			return cookie.ErrAmbiguousValue
+		}
+		value := strings.Join(parts, "|")
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = true
+		if err := m.Set(w, "secure_tags", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+
+	// Secure.Debug
+	if s.Secure.Debug {
+		value := strconv.FormatBool(s.Secure.Debug)
+
+		fieldOpts := o
+		fieldOpts.Encrypted = false
+		fieldOpts.Signed = false
+		if err := m.Set(w, "secure_debug", value, fieldOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package cookiegentest
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/syntaqx/cookie"
+)
+
+var manager = cookie.NewManager(
+	cookie.WithSigningKey([]byte("super-secret-key")),
+	cookie.WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")),
+)
+
+func fullCookies() Cookies {
+	page := 3
+	nickname := ""
+	seen := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+	return Cookies{
+		Base:     Base{UserID: 42, Token: "secret"},
+		Name:     "gopher",
+		Enabled:  true,
+		Int:      -1,
+		Int8:     -8,
+		Int16:    -16,
+		Int32:    -32,
+		Int64:    -64,
+		Uint:     1,
+		Uint8:    8,
+		Uint16:   16,
+		Uint32:   32,
+		Uint64:   64,
+		Float32:  1.5,
+		Float64:  math.Copysign(0, -1),
+		Since:    time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC),
+		Page:     &page,
+		Nickname: &nickname,
+		Seen:     &seen,
+		IDs:      []int{1, 2, 3},
+		Sizes:    []int64{4, 5},
+		Ratios:   []float64{0.25, 0.5},
+		Times:    []time.Time{seen, seen.Add(time.Hour)},
+		PageSize: 50,
+		Prefs:    Prefs{Theme: "dark", Tags: []string{"a", "b"}, Debug: true},
+		Secure:   Prefs{Theme: "dark", Tags: []string{}},
+	}
+}
+
+// requestWith returns a request with the cookies written by the reflection
+// path for src, replacing the values of the given cookies.
+func requestWith(t *testing.T, src Cookies, values map[string]string) *http.Request {
+	w := httptest.NewRecorder()
+	if err := manager.WriteToCookies(w, src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range w.Result().Cookies() {
+		if value, ok := values[c.Name]; ok {
+			c.Value = value
+			delete(values, c.Name)
+		}
+		req.AddCookie(c)
+	}
+	for name, value := range values {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	return req
+}
+
+func TestPopulateFromCookies_Parity(t *testing.T) {
+	tests := map[string]*http.Request{
+		"valid": requestWith(t, fullCookies(), nil),
+		"zero":  requestWith(t, Cookies{}, nil),
+		"empty": httptest.NewRequest(http.MethodGet, "/", nil),
+		"invalid": requestWith(t, fullCookies(), map[string]string{
+			"User-ID":        "tampered",
+			"Token":          "tampered",
+			"enabled":        "maybe",
+			"int":            "one",
+			"int8":           "300",
+			"int16":          "70000",
+			"int32":          "1.5",
+			"int64":          "",
+			"uint":           "-1",
+			"uint8":          "256",
+			"uint16":         "x",
+			"uint32":         "x",
+			"uint64":         "x",
+			"float32":        "1e40",
+			"float64":        "x",
+			"since":          "yesterday",
+			"page":           "x",
+			"seen":           "x",
+			"ids":            "1,x",
+			"sizes":          "1:x",
+			"ratios":         "0.5,x",
+			"times":          "x",
+			"page_size":      "x",
+			"prefs_debug":    "x",
+			"secure_theme":   "unsigned",
+			"secure_tags":    "unsigned",
+			"secure_debug":   "unsigned",
+			"unknown_cookie": "ignored",
+		}),
+		"signed values in unsigned fields": requestWith(t, fullCookies(), map[string]string{
+			"prefs_debug": "true",
+			"prefs_theme": "",
+			"prefs_tags":  "",
+		}),
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			var reflected Cookies
+			reflectedErr := manager.PopulateFromCookies(req, &reflected)
+
+			var generated Cookies
+			generatedErr := generated.PopulateFromCookies(manager, req)
+
+			if !reflect.DeepEqual(generated, reflected) {
+				t.Errorf("Unexpected result. Got: %+v, want: %+v", generated, reflected)
+			}
+			if !reflect.DeepEqual(generatedErr, reflectedErr) {
+				t.Errorf("Expected error '%v', but got '%v'", reflectedErr, generatedErr)
+			}
+		})
+	}
+}
+
+func TestPopulateFromCookies_ParityNilPointer(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	var dest *Cookies
+	reflectedErr := manager.PopulateFromCookies(req, dest)
+	generatedErr := dest.PopulateFromCookies(manager, req)
+
+	if generatedErr != reflectedErr {
+		t.Errorf("Expected error '%v', but got '%v'", reflectedErr, generatedErr)
+	}
+}
+
+func TestWriteToCookies_Parity(t *testing.T) {
+	emptySlices := Cookies{IDs: []int{}, Times: []time.Time{}}
+
+	tests := map[string]struct {
+		src     Cookies
+		manager *cookie.Manager
+	}{
+		"full":           {fullCookies(), manager},
+		"zero":           {Cookies{}, manager},
+		"empty slices":   {emptySlices, manager},
+		"missing keys":   {fullCookies(), cookie.NewManager()},
+		"no signing key": {Cookies{}, cookie.NewManager()},
+		"ambiguous tags": {Cookies{Prefs: Prefs{Tags: []string{"a|b"}}}, manager},
+		"single empty":   {Cookies{IDs: []int{}, Prefs: Prefs{Tags: []string{""}}}, manager},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := cookie.Options{Path: "/", HttpOnly: true, Signed: true}
+
+			reflectedW := httptest.NewRecorder()
+			reflectedErr := tt.manager.WriteToCookies(reflectedW, tt.src, opts)
+
+			generatedW := httptest.NewRecorder()
+			generatedErr := tt.src.WriteToCookies(tt.manager, generatedW, opts)
+
+			if !reflect.DeepEqual(generatedErr, reflectedErr) {
+				t.Errorf("Expected error '%v', but got '%v'", reflectedErr, generatedErr)
+			}
+
+			reflected := decryptTokens(t, tt.manager, reflectedW.Result().Cookies())
+			generated := decryptTokens(t, tt.manager, generatedW.Result().Cookies())
+			if !reflect.DeepEqual(generated, reflected) {
+				t.Errorf("Unexpected cookies. Got: %v, want: %v", generated, reflected)
+			}
+		})
+	}
+}
+
+// decryptTokens replaces the value of the encrypted Token cookie, which uses
+// a random nonce, with its decrypted value.
+func decryptTokens(t *testing.T, m *cookie.Manager, cookies []*http.Cookie) []*http.Cookie {
+	for _, c := range cookies {
+		if c.Name != "Token" {
+			continue
+		}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(c)
+		value, err := m.GetEncrypted(req, c.Name)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		c.Value = value
+		c.Raw = ""
+	}
+	return cookies
+}
@@ -0,0 +1,53 @@
+// Package cookiegentest declares structs bound both by reflection and by code
+// generated with cookiegen, so tests can check that the two paths agree.
+package cookiegentest
+
+import "time"
+
+//go:generate go run ../../cmd/cookiegen -type=Cookies -output=cookies_cookiegen.go
+
+// Base is embedded in Cookies.
+type Base struct {
+	UserID int    `cookie:"User-ID,signed"`
+	Token  string `cookie:"Token,encrypted,omitempty"`
+}
+
+// Prefs is inlined in Cookies.
+type Prefs struct {
+	Theme string   `cookie:"theme,default=light"`
+	Tags  []string `cookie:"tags,omitempty,sep=|"`
+	Debug bool     `cookie:"debug,unsigned"`
+}
+
+// Cookies has a field of every shape supported by cookiegen.
+type Cookies struct {
+	Base
+
+	Name      string      `cookie:"name"`
+	Enabled   bool        `cookie:"enabled,omitempty"`
+	Int       int         `cookie:"int,omitempty"`
+	Int8      int8        `cookie:"int8,omitempty"`
+	Int16     int16       `cookie:"int16,omitempty"`
+	Int32     int32       `cookie:"int32,omitempty"`
+	Int64     int64       `cookie:"int64,omitempty"`
+	Uint      uint        `cookie:"uint,omitempty"`
+	Uint8     uint8       `cookie:"uint8,omitempty"`
+	Uint16    uint16      `cookie:"uint16,omitempty"`
+	Uint32    uint32      `cookie:"uint32,omitempty"`
+	Uint64    uint64      `cookie:"uint64,omitempty"`
+	Float32   float32     `cookie:"float32,omitempty"`
+	Float64   float64     `cookie:"float64,omitempty"`
+	Since     time.Time   `cookie:"since,omitempty"`
+	Page      *int        `cookie:"page"`
+	Nickname  *string     `cookie:"nickname"`
+	Seen      *time.Time  `cookie:"seen"`
+	IDs       []int       `cookie:"ids,omitempty"`
+	Sizes     []int64     `cookie:"sizes,omitempty,sep=:"`
+	Ratios    []float64   `cookie:"ratios,omitempty"`
+	Times     []time.Time `cookie:"times,omitempty"`
+	PageSize  int         `cookie:"page_size,default=20"`
+	Prefs     Prefs       `cookie:"prefs_,inline"`
+	Secure    Prefs       `cookie:"secure_,inline,signed,omitempty"`
+	Untagged  string
+	unexposed string
+}
@@ -0,0 +1,62 @@
+package cookie
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+// SetJSON sets the value of a cookie to the JSON encoding of v, encoded with
+// base64url so it is safe to use as a cookie value. It is signed or encrypted
+// according to the given options.
+func (m *Manager) SetJSON(w http.ResponseWriter, name string, v interface{}, opts ...Options) error {
+	value, err := encodeJSONValue(v)
+	if err != nil {
+		return err
+	}
+	return m.Set(w, name, value, opts...)
+}
+
+// GetJSON retrieves a cookie set by SetJSON and decodes its value into v,
+// which must be a pointer. The value is verified or decrypted according to the
+// Signed and Encrypted fields of the given options, which should match those
+// it was set with.
+func (m *Manager) GetJSON(r *http.Request, name string, v interface{}, opts ...Options) error {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	var value string
+	var err error
+	switch {
+	case o.Encrypted:
+		value, err = m.GetEncrypted(r, name)
+	case o.Signed:
+		value, err = m.GetSigned(r, name)
+	default:
+		value, err = m.Get(r, name)
+	}
+	if err != nil {
+		return err
+	}
+	return decodeJSONValue(value, v)
+}
+
+// encodeJSONValue encodes v as base64url JSON.
+func encodeJSONValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// decodeJSONValue decodes base64url JSON encoded by encodeJSONValue into v.
+func decodeJSONValue(value string, v interface{}) error {
+	data, err := base64.URLEncoding.DecodeString(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
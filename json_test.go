@@ -0,0 +1,144 @@
+package cookie
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type jsonPrefs struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+func TestManager_SetJSON_GetJSON(t *testing.T) {
+	tests := map[string]struct {
+		manager *Manager
+		opts    Options
+	}{
+		"plain":     {unsignedManager, Options{}},
+		"signed":    {signedManager, Options{Signed: true}},
+		"encrypted": {encryptedManager, Options{Encrypted: true}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected := jsonPrefs{Theme: "dark", Tags: []string{"a", "b"}}
+
+			w := httptest.NewRecorder()
+			if err := tt.manager.SetJSON(w, "prefs", expected, tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cookie := w.Result().Cookies()[0]
+			if strings.ContainsAny(cookie.Value, `{}",`) {
+				t.Errorf("Expected cookie-safe value, but got '%s'", cookie.Value)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(cookie)
+
+			var prefs jsonPrefs
+			if err := tt.manager.GetJSON(req, "prefs", &prefs, tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(prefs, expected) {
+				t.Errorf("Unexpected result. Got: %+v, want: %+v", prefs, expected)
+			}
+		})
+	}
+}
+
+func TestManager_GetJSON_Errors(t *testing.T) {
+	var prefs jsonPrefs
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := unsignedManager.GetJSON(req, "prefs", &prefs); err != http.ErrNoCookie {
+		t.Errorf("Expected error '%v', but got '%v'", http.ErrNoCookie, err)
+	}
+
+	req.AddCookie(&http.Cookie{Name: "prefs", Value: "not base64!"})
+	if err := unsignedManager.GetJSON(req, "prefs", &prefs); err == nil {
+		t.Error("Expected error for invalid base64, but got nil")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "prefs", Value: "bm90IGpzb24="})
+	if err := unsignedManager.GetJSON(req, "prefs", &prefs); err == nil {
+		t.Error("Expected error for invalid JSON, but got nil")
+	}
+
+	w := httptest.NewRecorder()
+	if err := signedManager.SetJSON(w, "prefs", jsonPrefs{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+	if err := signedManager.GetJSON(req, "prefs", &prefs, Options{Signed: true}); err == nil {
+		t.Error("Expected error for unsigned value, but got nil")
+	}
+}
+
+func TestManager_SetJSON_Unsupported(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := unsignedManager.SetJSON(w, "prefs", make(chan int)); err == nil {
+		t.Error("Expected error, but got nil")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be set")
+	}
+}
+
+func TestPopulateFromCookies_JSON(t *testing.T) {
+	type MyStruct struct {
+		Prefs    jsonPrefs      `cookie:"prefs,json"`
+		Cart     map[string]int `cookie:"cart,json,signed"`
+		Recent   []int          `cookie:"recent,json,omitempty"`
+		Defaults *jsonPrefs     `cookie:"defaults,json,default={\"theme\":\"light\"}"`
+	}
+
+	src := MyStruct{
+		Prefs: jsonPrefs{Theme: "dark", Tags: []string{"a"}},
+		Cart:  map[string]int{"apple": 2},
+	}
+
+	w := httptest.NewRecorder()
+	if err := signedManager.WriteToCookies(w, src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := signedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := src
+	expected.Defaults = &jsonPrefs{Theme: "light"}
+	if !reflect.DeepEqual(dest, &expected) {
+		t.Errorf("Unexpected result. Got: %+v, want: %+v", dest, &expected)
+	}
+}
+
+func TestPopulateFromCookies_InvalidJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "prefs", Value: "bm90IGpzb24="})
+
+	type MyStruct struct {
+		Prefs jsonPrefs `cookie:"prefs,json"`
+	}
+
+	err := unsignedManager.PopulateFromCookies(req, &MyStruct{})
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "Prefs" {
+		t.Errorf("Expected FieldError for Prefs, but got '%v'", err)
+	}
+}
@@ -0,0 +1,52 @@
+package cookie
+
+import (
+	"encoding"
+	"encoding/base64"
+	"reflect"
+)
+
+// unmarshalFieldValue sets the value of a struct field implementing
+// encoding.TextUnmarshaler, or encoding.BinaryUnmarshaler from a base64 value,
+// either directly or through its pointer. It reports whether the field
+// implements either interface.
+func unmarshalFieldValue(fieldVal reflect.Value, value string) (bool, error) {
+	if !fieldVal.CanAddr() {
+		return false, nil
+	}
+
+	switch u := fieldVal.Addr().Interface().(type) {
+	case encoding.TextUnmarshaler:
+		return true, u.UnmarshalText([]byte(value))
+	case encoding.BinaryUnmarshaler:
+		data, err := base64.URLEncoding.DecodeString(value)
+		if err != nil {
+			return true, err
+		}
+		return true, u.UnmarshalBinary(data)
+	}
+	return false, nil
+}
+
+// marshalFieldValue formats the value of a struct field implementing
+// encoding.TextMarshaler, or encoding.BinaryMarshaler as a base64 value,
+// either directly or through its pointer. It reports whether the field
+// implements either interface.
+func marshalFieldValue(fieldVal reflect.Value) (string, bool, error) {
+	candidates := []reflect.Value{fieldVal}
+	if fieldVal.CanAddr() {
+		candidates = append(candidates, fieldVal.Addr())
+	}
+
+	for _, candidate := range candidates {
+		switch m := candidate.Interface().(type) {
+		case encoding.TextMarshaler:
+			text, err := m.MarshalText()
+			return string(text), true, err
+		case encoding.BinaryMarshaler:
+			data, err := m.MarshalBinary()
+			return base64.URLEncoding.EncodeToString(data), true, err
+		}
+	}
+	return "", false, nil
+}
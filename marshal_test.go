@@ -0,0 +1,193 @@
+package cookie
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// textValue implements encoding.TextMarshaler and encoding.TextUnmarshaler
+// with pointer receivers.
+type textValue struct {
+	value string
+}
+
+func (v *textValue) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(v.value)), nil
+}
+
+func (v *textValue) UnmarshalText(text []byte) error {
+	if string(text) == "invalid" {
+		return errors.New("invalid text value")
+	}
+	v.value = strings.ToLower(string(text))
+	return nil
+}
+
+// binaryValue implements encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler.
+type binaryValue struct {
+	data []byte
+}
+
+func (v binaryValue) MarshalBinary() ([]byte, error) {
+	return v.data, nil
+}
+
+func (v *binaryValue) UnmarshalBinary(data []byte) error {
+	v.data = data
+	return nil
+}
+
+func TestPopulateFromCookies_TextUnmarshaler(t *testing.T) {
+	DefaultManager = unsignedManager
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "text", Value: "HELLO"})
+	req.AddCookie(&http.Cookie{Name: "binary", Value: "AQID"})
+	req.AddCookie(&http.Cookie{Name: "ip", Value: "192.168.0.1"})
+
+	type MyStruct struct {
+		Text   textValue   `cookie:"text"`
+		Binary binaryValue `cookie:"binary"`
+		IP     net.IP      `cookie:"ip"`
+	}
+
+	dest := &MyStruct{}
+	err := PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := &MyStruct{
+		Text:   textValue{value: "hello"},
+		Binary: binaryValue{data: []byte{1, 2, 3}},
+		IP:     net.ParseIP("192.168.0.1"),
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", dest, expected)
+	}
+}
+
+func TestPopulateFromCookies_TextUnmarshalerError(t *testing.T) {
+	tests := map[string]struct {
+		name  string
+		value string
+	}{
+		"text":   {name: "text", value: "invalid"},
+		"binary": {name: "binary", value: "invalid base64!"},
+	}
+
+	type MyStruct struct {
+		Text   textValue   `cookie:"text,omitempty"`
+		Binary binaryValue `cookie:"binary,omitempty"`
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: tt.name, Value: tt.value})
+
+			err := unsignedManager.PopulateFromCookies(req, &MyStruct{})
+			if err == nil {
+				t.Error("Expected error, but got nil")
+			}
+		})
+	}
+}
+
+func TestPopulateFromCookies_CustomHandlerOverTextUnmarshaler(t *testing.T) {
+	manager := NewManager(
+		WithCustomHandler(reflect.TypeOf(textValue{}), func(value string) (interface{}, error) {
+			return textValue{value: "custom"}, nil
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "text", Value: "HELLO"})
+
+	type MyStruct struct {
+		Text textValue `cookie:"text"`
+	}
+
+	dest := &MyStruct{}
+	if err := manager.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if dest.Text.value != "custom" {
+		t.Errorf("Expected custom handler to take precedence, but got '%s'", dest.Text.value)
+	}
+}
+
+func TestWriteToCookies_TextMarshaler(t *testing.T) {
+	type MyStruct struct {
+		Text   textValue   `cookie:"text"`
+		Binary binaryValue `cookie:"binary"`
+		IP     net.IP      `cookie:"ip"`
+	}
+
+	src := MyStruct{
+		Text:   textValue{value: "hello"},
+		Binary: binaryValue{data: []byte{1, 2, 3}},
+		IP:     net.ParseIP("192.168.0.1"),
+	}
+
+	w := httptest.NewRecorder()
+	if err := unsignedManager.WriteToCookies(w, src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedValues := map[string]string{
+		"text":   "HELLO",
+		"binary": "AQID",
+		"ip":     "192.168.0.1",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Value != expectedValues[cookie.Name] {
+			t.Errorf("Expected cookie '%s' value '%s', but got '%s'", cookie.Name, expectedValues[cookie.Name], cookie.Value)
+		}
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := unsignedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*dest, src) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", *dest, src)
+	}
+}
+
+func TestWriteToCookies_NilTextMarshaler(t *testing.T) {
+	type MyStruct struct {
+		IPs []*netip.Addr `cookie:"ips"`
+	}
+
+	w := httptest.NewRecorder()
+	err := unsignedManager.WriteToCookies(w, MyStruct{IPs: []*netip.Addr{nil}})
+	if err != ErrAmbiguousValue {
+		t.Errorf("Expected error '%v', but got '%v'", ErrAmbiguousValue, err)
+	}
+
+	w = httptest.NewRecorder()
+	if err := DefineWith[*netip.Addr](unsignedManager, "ip", Options{}).Set(w, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].Value != "" {
+		t.Errorf("Expected empty cookie, but got %v", cookies)
+	}
+}
+
+func TestUnmarshalFieldValue_NotAddressable(t *testing.T) {
+	ok, err := unmarshalFieldValue(reflect.ValueOf(textValue{}), "value")
+	if ok || err != nil {
+		t.Errorf("Expected unaddressable value to be skipped, but got %v, %v", ok, err)
+	}
+}
@@ -1,6 +1,8 @@
 package cookie
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -8,7 +10,22 @@ import (
 	"time"
 )
 
-// PopulateFromCookies populates a struct with cookie values.
+// PopulateFromCookies populates a struct with cookie values. Every field is
+// populated even if some fail, in which case the failures are returned as
+// FieldErrors.
+//
+// Populated fields are then checked against the validation rules of their tag:
+// required, min=, max=, len=, oneof= with space separated values, pattern=, and
+// any validator registered with WithValidator. Failures are reported as a
+// ValidationError. Fields tagged required also fail when their cookie is
+// absent, even if they are pointers or tagged omitempty.
+//
+// Fields tagged json are decoded from base64url JSON, as set by SetJSON, into
+// any type supported by encoding/json.
+//
+// Fields tagged with a default= option are set from its value when their
+// cookie is absent, or also when it fails verification or decryption if they
+// are tagged fallback.
 func (m *Manager) PopulateFromCookies(r *http.Request, dest interface{}) error {
 	v := reflect.ValueOf(dest)
 	if v.Kind() != reflect.Pointer || v.IsNil() {
@@ -16,62 +33,90 @@ func (m *Manager) PopulateFromCookies(r *http.Request, dest interface{}) error {
 	}
 	v = v.Elem()
 
-	t := v.Type()
-	for i := 0; i < v.NumField(); i++ {
-		field := t.Field(i)
-		tag := field.Tag.Get("cookie")
-		if tag == "" {
-			continue
-		}
-
-		parts := strings.Split(tag, ",")
-		name := parts[0]
-		signed := false
-		unsigned := false
-		omitempty := false
-
-		for _, part := range parts[1:] {
-			switch part {
-			case "signed":
-				signed = true
-			case "unsigned":
-				unsigned = true
-			case "omitempty":
-				omitempty = true
-			}
-		}
-
+	var errs FieldErrors
+	err := m.walkFields(v, func(path string, fieldVal reflect.Value, opts tagOptions) error {
 		var value string
 		var err error
-		if signed && !unsigned {
-			value, err = m.GetSigned(r, name)
+		if opts.encrypted {
+			value, err = m.GetEncrypted(r, opts.name)
+		} else if opts.signed && !opts.unsigned {
+			value, err = m.GetSigned(r, opts.name)
 		} else {
-			value, err = m.Get(r, name)
+			value, err = m.Get(r, opts.name)
+		}
+		isDefault := false
+		if err != nil && opts.hasDefault && (err == http.ErrNoCookie || opts.fallback && isVerificationError(err)) {
+			value, err, isDefault = opts.def, nil, true
+		}
+		if err == http.ErrNoCookie {
+			// Fields tagged required fail validation when their cookie is
+			// absent, whatever their kind.
+			if rule, ok := findRule(opts.rules, "required"); ok {
+				errs = append(errs, &FieldError{Field: path, Cookie: opts.name, Err: &ValidationError{Rule: rule.String(), Err: err}})
+				return nil
+			}
 		}
 		if err != nil {
-			if err == http.ErrNoCookie && omitempty {
-				continue
+			// Pointer fields are left nil when their cookie is absent, so they
+			// are always optional.
+			if err == http.ErrNoCookie && (opts.omitempty || fieldVal.Kind() == reflect.Pointer) {
+				return nil
 			}
-			return err
+			if c, cookieErr := r.Cookie(opts.name); cookieErr == nil {
+				value = c.Value
+			}
+			errs = append(errs, &FieldError{Field: path, Cookie: opts.name, Value: value, Err: err})
+			return nil
 		}
 
-		fieldVal := v.Field(i)
-
 		// TODO: Is this necessary? How can I test it?
 		// if !fieldVal.CanSet() {
-		// 	continue
+		// 	return nil
 		// }
 
-		err = m.setFieldValue(fieldVal, value)
+		switch {
+		case opts.json && isDefault:
+			// Defaults of JSON fields are written as plain JSON in the tag.
+			err = json.Unmarshal([]byte(value), fieldVal.Addr().Interface())
+		case opts.json:
+			err = decodeJSONValue(value, fieldVal.Addr().Interface())
+		default:
+			err = m.setFieldValue(fieldVal, value, opts.separator())
+		}
 		if err != nil {
-			return err
+			errs = append(errs, &FieldError{Field: path, Cookie: opts.name, Value: value, Err: err})
+			return nil
 		}
+		if err := m.validateField(fieldVal, opts.rules, opts.separator()); err != nil {
+			errs = append(errs, &FieldError{Field: path, Cookie: opts.name, Value: value, Err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
 	}
 	return nil
 }
 
-// setFieldValue sets the value of a struct field based on its type.
-func (m *Manager) setFieldValue(fieldVal reflect.Value, value string) error {
+// isVerificationError reports whether err means a signed or encrypted cookie
+// could not be trusted, as opposed to being absent or misconfigured.
+func isVerificationError(err error) bool {
+	switch err {
+	case ErrInvalidSignedCookieFormat, ErrInvalidCookieSignature, ErrSignatureExpired,
+		ErrUnknownSigningKey, ErrInvalidEncryptedCookie, ErrInvalidCompressedCookie, ErrInvalidChunkedCookie:
+		return true
+	}
+	_, corrupt := err.(base64.CorruptInputError)
+	return corrupt
+}
+
+// setFieldValue sets the value of a struct field based on its type. Slice
+// elements and map entries are separated by sep, and an empty value is an
+// empty slice or map.
+func (m *Manager) setFieldValue(fieldVal reflect.Value, value, sep string) error {
 	if handler, ok := m.customHandlers[fieldVal.Type()]; ok {
 		customValue, err := handler(value)
 		if err != nil {
@@ -81,6 +126,23 @@ func (m *Manager) setFieldValue(fieldVal reflect.Value, value string) error {
 		return nil
 	}
 
+	if fieldVal.Kind() == reflect.Pointer {
+		ptr := reflect.New(fieldVal.Type().Elem())
+		if err := m.setFieldValue(ptr.Elem(), value, sep); err != nil {
+			return err
+		}
+		fieldVal.Set(ptr)
+		return nil
+	}
+
+	// time.Time implements encoding.TextUnmarshaler, but is parsed below so
+	// values keep being read as RFC 3339.
+	if fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+		if ok, err := unmarshalFieldValue(fieldVal, value); ok {
+			return err
+		}
+	}
+
 	switch fieldVal.Kind() {
 	case reflect.Bool:
 		boolVal, err := strconv.ParseBool(value)
@@ -109,11 +171,15 @@ func (m *Manager) setFieldValue(fieldVal reflect.Value, value string) error {
 		}
 		fieldVal.SetFloat(floatVal)
 	case reflect.Slice:
-		switch fieldVal.Type().Elem().Kind() {
-		case reflect.String:
-			fieldVal.Set(reflect.ValueOf(strings.Split(value, ",")))
-		case reflect.Int:
-			strSlice := strings.Split(value, ",")
+		if value == "" {
+			fieldVal.Set(reflect.MakeSlice(fieldVal.Type(), 0, 0))
+			return nil
+		}
+		switch fieldVal.Type() {
+		case reflect.TypeOf([]string(nil)):
+			fieldVal.Set(reflect.ValueOf(strings.Split(value, sep)))
+		case reflect.TypeOf([]int(nil)):
+			strSlice := strings.Split(value, sep)
 			intSlice := make([]int, len(strSlice))
 			for i, str := range strSlice {
 				intVal, err := strconv.Atoi(str)
@@ -123,7 +189,37 @@ func (m *Manager) setFieldValue(fieldVal reflect.Value, value string) error {
 				intSlice[i] = intVal
 			}
 			fieldVal.Set(reflect.ValueOf(intSlice))
+		default:
+			strSlice := strings.Split(value, sep)
+			slice := reflect.MakeSlice(fieldVal.Type(), len(strSlice), len(strSlice))
+			for i, str := range strSlice {
+				if err := m.setFieldValue(slice.Index(i), str, sep); err != nil {
+					return err
+				}
+			}
+			fieldVal.Set(slice)
+		}
+	case reflect.Map:
+		mapType := fieldVal.Type()
+		mapVal := reflect.MakeMap(mapType)
+		if value != "" {
+			for _, entry := range strings.Split(value, sep) {
+				k, v, ok := strings.Cut(entry, "=")
+				if !ok {
+					return ErrInvalidMapEntry
+				}
+				key := reflect.New(mapType.Key()).Elem()
+				if err := m.setFieldValue(key, k, sep); err != nil {
+					return err
+				}
+				elem := reflect.New(mapType.Elem()).Elem()
+				if err := m.setFieldValue(elem, v, sep); err != nil {
+					return err
+				}
+				mapVal.SetMapIndex(key, elem)
+			}
 		}
+		fieldVal.Set(mapVal)
 	case reflect.Struct:
 		if fieldVal.Type() == reflect.TypeOf(time.Time{}) {
 			timeVal, err := time.Parse(time.RFC3339, value)
@@ -131,6 +227,8 @@ func (m *Manager) setFieldValue(fieldVal reflect.Value, value string) error {
 				return err
 			}
 			fieldVal.Set(reflect.ValueOf(timeVal))
+		} else {
+			return &ErrUnsupportedType{Type: fieldVal.Type()}
 		}
 	default:
 		return &ErrUnsupportedType{Type: fieldVal.Type()}
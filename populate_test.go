@@ -1,9 +1,11 @@
 package cookie
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -88,7 +90,7 @@ func TestPopulateFromCookies_ErrNoCookie(t *testing.T) {
 
 	dest := &MyStruct{}
 	err := unsignedManager.PopulateFromCookies(req, dest)
-	if err != http.ErrNoCookie {
+	if !errors.Is(err, http.ErrNoCookie) {
 		t.Errorf("Unexpected error: %v", err)
 	}
 }
@@ -108,7 +110,7 @@ func TestPopulateFromCookies_ErrUnsupportedType(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "cookie: unsupported type: complex128"
+	expectedError := "cookie: field Field (cookie \"cookie\"): cookie: unsupported type: complex128"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -128,7 +130,7 @@ func TestPopulateFromCookies_InvalidBoolean(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "strconv.ParseBool: parsing \"invalid\": invalid syntax"
+	expectedError := "cookie: field Field (cookie \"cookie\"): strconv.ParseBool: parsing \"invalid\": invalid syntax"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -148,7 +150,7 @@ func TestPopulateFromCookies_InvalidInteger(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "strconv.ParseInt: parsing \"invalid\": invalid syntax"
+	expectedError := "cookie: field Field (cookie \"cookie\"): strconv.ParseInt: parsing \"invalid\": invalid syntax"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -168,7 +170,7 @@ func TestPopulateFromCookies_InvalidUnsignedInteger(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "strconv.ParseUint: parsing \"-1\": invalid syntax"
+	expectedError := "cookie: field Field (cookie \"cookie\"): strconv.ParseUint: parsing \"-1\": invalid syntax"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -188,7 +190,7 @@ func TestPopulateFromCookies_InvalidFloat(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "strconv.ParseFloat: parsing \"invalid\": invalid syntax"
+	expectedError := "cookie: field Field (cookie \"cookie\"): strconv.ParseFloat: parsing \"invalid\": invalid syntax"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -208,7 +210,7 @@ func TestPopulateFromCookies_InvalidIntSlice(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "strconv.Atoi: parsing \"invalid\": invalid syntax"
+	expectedError := "cookie: field Field (cookie \"cookie\"): strconv.Atoi: parsing \"invalid\": invalid syntax"
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
@@ -228,8 +230,418 @@ func TestPopulateFromCookies_InvalidTimestamp(t *testing.T) {
 		t.Error("Expected error, but got nil")
 	}
 
-	expectedError := "parsing time \"invalid\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"invalid\" as \"2006\""
+	expectedError := "cookie: field Field (cookie \"cookie\"): parsing time \"invalid\" as \"2006-01-02T15:04:05Z07:00\": cannot parse \"invalid\" as \"2006\""
 	if err.Error() != expectedError {
 		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
 	}
 }
+
+func TestPopulateFromCookies_Encrypted(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := encryptedManager.SetEncrypted(w, "cookie", "secret"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	type MyStruct struct {
+		Field string `cookie:"cookie,encrypted"`
+	}
+
+	dest := &MyStruct{}
+	err := encryptedManager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if dest.Field != "secret" {
+		t.Errorf("Expected value '%s', but got '%s'", "secret", dest.Field)
+	}
+}
+
+func TestPopulateFromCookies_BoundSignatures(t *testing.T) {
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithBoundSignatures(""),
+	)
+
+	w := httptest.NewRecorder()
+	if err := manager.SetSigned(w, "User-ID", "12345"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	signed := w.Result().Cookies()[0].Value
+
+	type MyStruct struct {
+		UserID  int  `cookie:"User-ID,signed"`
+		IsAdmin bool `cookie:"Is-Admin,signed"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "User-ID", Value: signed})
+	req.AddCookie(&http.Cookie{Name: "Is-Admin", Value: signed})
+
+	err := manager.PopulateFromCookies(req, &MyStruct{})
+	if !errors.Is(err, ErrInvalidCookieSignature) {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
+
+func TestPopulateFromCookies_NestedStructs(t *testing.T) {
+	type Base struct {
+		UserID int `cookie:"User-ID"`
+	}
+
+	type Prefs struct {
+		Theme string `cookie:"theme"`
+		Lang  string `cookie:"lang,omitempty"`
+	}
+
+	type MyStruct struct {
+		Base
+		Prefs Prefs `cookie:"prefs_,inline"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "User-ID", Value: "123"})
+	req.AddCookie(&http.Cookie{Name: "prefs_theme", Value: "dark"})
+
+	dest := &MyStruct{}
+	err := unsignedManager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	expected := &MyStruct{
+		Base:  Base{UserID: 123},
+		Prefs: Prefs{Theme: "dark"},
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", dest, expected)
+	}
+}
+
+func TestPopulateFromCookies_UnsupportedStruct(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: "test"})
+
+	type Nested struct {
+		Field string
+	}
+
+	type MyStruct struct {
+		Field Nested `cookie:"cookie"`
+	}
+
+	err := unsignedManager.PopulateFromCookies(req, &MyStruct{})
+	var unsupported *ErrUnsupportedType
+	if !errors.As(err, &unsupported) {
+		t.Errorf("Expected ErrUnsupportedType, but got '%v'", err)
+	}
+}
+
+func TestPopulateFromCookies_Pointers(t *testing.T) {
+	manager := NewManager(
+		WithCustomHandler(reflect.TypeOf(CustomType{}), func(value string) (interface{}, error) {
+			return CustomTypeFromString(value)
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "int", Value: "0"})
+	req.AddCookie(&http.Cookie{Name: "string", Value: ""})
+	req.AddCookie(&http.Cookie{Name: "time", Value: "2021-01-02T15:04:05Z"})
+	req.AddCookie(&http.Cookie{Name: "custom", Value: "test"})
+
+	type MyStruct struct {
+		Int       *int        `cookie:"int"`
+		String    *string     `cookie:"string"`
+		Time      *time.Time  `cookie:"time"`
+		Custom    *CustomType `cookie:"custom"`
+		Absent    *int        `cookie:"absent"`
+		AbsentPtr **string    `cookie:"absent_ptr"`
+	}
+
+	dest := &MyStruct{}
+	err := manager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if dest.Int == nil || *dest.Int != 0 {
+		t.Errorf("Expected pointer to 0, but got %v", dest.Int)
+	}
+	if dest.String == nil || *dest.String != "" {
+		t.Errorf("Expected pointer to empty string, but got %v", dest.String)
+	}
+	if dest.Time == nil || !dest.Time.Equal(time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Errorf("Expected pointer to time, but got %v", dest.Time)
+	}
+	if dest.Custom == nil {
+		t.Error("Expected pointer to custom type, but got nil")
+	}
+	if dest.Absent != nil || dest.AbsentPtr != nil {
+		t.Error("Expected pointers of absent cookies to be nil")
+	}
+}
+
+func TestPopulateFromCookies_PointerError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: "invalid"})
+
+	type MyStruct struct {
+		Field *int `cookie:"cookie"`
+	}
+
+	dest := &MyStruct{}
+	err := unsignedManager.PopulateFromCookies(req, dest)
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+	if dest.Field != nil {
+		t.Errorf("Expected pointer to be left nil, but got %v", dest.Field)
+	}
+}
+
+func TestPopulateFromCookies_SlicesAndMaps(t *testing.T) {
+	manager := NewManager(
+		WithCustomHandler(reflect.TypeOf(CustomType{}), func(value string) (interface{}, error) {
+			return CustomTypeFromString(value)
+		}),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "floats", Value: "1.5,2.5"})
+	req.AddCookie(&http.Cookie{Name: "bools", Value: "true,false"})
+	req.AddCookie(&http.Cookie{Name: "uints", Value: "1|2|3"})
+	req.AddCookie(&http.Cookie{Name: "times", Value: "2021-01-02T15:04:05Z,2022-01-02T15:04:05Z"})
+	req.AddCookie(&http.Cookie{Name: "customs", Value: "a,b"})
+	req.AddCookie(&http.Cookie{Name: "named", Value: "a,b"})
+	req.AddCookie(&http.Cookie{Name: "strmap", Value: "theme=dark,lang=en"})
+	req.AddCookie(&http.Cookie{Name: "intmap", Value: "a=1|b=2"})
+	req.AddCookie(&http.Cookie{Name: "emptymap", Value: ""})
+
+	type Names []string
+
+	type MyStruct struct {
+		Floats   []float64         `cookie:"floats"`
+		Bools    []bool            `cookie:"bools"`
+		Uints    []uint8           `cookie:"uints,sep=|"`
+		Times    []time.Time       `cookie:"times"`
+		Customs  []CustomType      `cookie:"customs"`
+		Named    Names             `cookie:"named"`
+		StrMap   map[string]string `cookie:"strmap"`
+		IntMap   map[string]int    `cookie:"intmap,sep=|"`
+		EmptyMap map[string]int    `cookie:"emptymap"`
+	}
+
+	dest := &MyStruct{}
+	err := manager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := &MyStruct{
+		Floats: []float64{1.5, 2.5},
+		Bools:  []bool{true, false},
+		Uints:  []uint8{1, 2, 3},
+		Times: []time.Time{
+			time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+			time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		Customs:  []CustomType{{}, {}},
+		Named:    Names{"a", "b"},
+		StrMap:   map[string]string{"theme": "dark", "lang": "en"},
+		IntMap:   map[string]int{"a": 1, "b": 2},
+		EmptyMap: map[string]int{},
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", dest, expected)
+	}
+}
+
+func TestPopulateFromCookies_SlicesAndMapsErrors(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		dest  interface{}
+	}{
+		"slice element": {
+			value: "1.5,invalid",
+			dest: &struct {
+				Field []float64 `cookie:"cookie"`
+			}{},
+		},
+		"unsupported element": {
+			value: "1",
+			dest: &struct {
+				Field []complex128 `cookie:"cookie"`
+			}{},
+		},
+		"map entry": {
+			value: "invalid",
+			dest: &struct {
+				Field map[string]string `cookie:"cookie"`
+			}{},
+		},
+		"map key": {
+			value: "invalid=1",
+			dest: &struct {
+				Field map[int]int `cookie:"cookie"`
+			}{},
+		},
+		"map value": {
+			value: "a=invalid",
+			dest: &struct {
+				Field map[string]int `cookie:"cookie"`
+			}{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "cookie", Value: tt.value})
+
+			err := unsignedManager.PopulateFromCookies(req, tt.dest)
+			if err == nil {
+				t.Error("Expected error, but got nil")
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: "invalid"})
+
+	err := unsignedManager.PopulateFromCookies(req, tests["map entry"].dest)
+	if !errors.Is(err, ErrInvalidMapEntry) {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidMapEntry, err)
+	}
+}
+
+func TestPopulateFromCookies_FieldErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "page", Value: "abc"})
+	req.AddCookie(&http.Cookie{Name: "prefs_dark", Value: "maybe"})
+	req.AddCookie(&http.Cookie{Name: "name", Value: "gopher"})
+
+	type Prefs struct {
+		Dark bool `cookie:"dark"`
+	}
+
+	type MyStruct struct {
+		Page  int    `cookie:"page"`
+		Name  string `cookie:"name"`
+		Token string `cookie:"token"`
+		Prefs Prefs  `cookie:"prefs_,inline"`
+	}
+
+	dest := &MyStruct{}
+	err := unsignedManager.PopulateFromCookies(req, dest)
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) {
+		t.Fatalf("Expected FieldErrors, but got '%v'", err)
+	}
+
+	expected := []struct {
+		field, cookie, value string
+	}{
+		{"Page", "page", "abc"},
+		{"Token", "token", ""},
+		{"Prefs.Dark", "prefs_dark", "maybe"},
+	}
+	if len(fieldErrs) != len(expected) {
+		t.Fatalf("Expected %d field errors, but got %d: %v", len(expected), len(fieldErrs), err)
+	}
+	for i, e := range expected {
+		if fieldErrs[i].Field != e.field || fieldErrs[i].Cookie != e.cookie || fieldErrs[i].Value != e.value {
+			t.Errorf("Unexpected field error %d: %+v", i, fieldErrs[i])
+		}
+	}
+
+	if !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("Expected error to match http.ErrNoCookie, but got '%v'", err)
+	}
+
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("Expected error to match *strconv.NumError, but got '%v'", err)
+	}
+
+	if dest.Name != "gopher" {
+		t.Errorf("Expected value '%s', but got '%s'", "gopher", dest.Name)
+	}
+}
+
+func TestPopulateFromCookies_FieldErrorsInvalidSignature(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "cookie", Value: "tampered"})
+
+	type MyStruct struct {
+		Field string `cookie:"cookie,signed"`
+	}
+
+	err := signedManager.PopulateFromCookies(req, &MyStruct{})
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("Expected FieldError, but got '%v'", err)
+	}
+	if fieldErr.Value != "tampered" {
+		t.Errorf("Expected value '%s', but got '%s'", "tampered", fieldErr.Value)
+	}
+}
+
+func TestPopulateFromCookies_Defaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "page", Value: "3"})
+	req.AddCookie(&http.Cookie{Name: "token", Value: "tampered"})
+	req.AddCookie(&http.Cookie{Name: "strict", Value: "tampered"})
+
+	type MyStruct struct {
+		Theme    string   `cookie:"theme,default=light"`
+		Page     int      `cookie:"page,default=1"`
+		PageSize *int     `cookie:"page_size,default=20"`
+		Tags     []string `cookie:"tags,default=a,b"`
+		Token    string   `cookie:"token,signed,fallback,default=guest"`
+		Strict   string   `cookie:"strict,signed,default=guest"`
+	}
+
+	dest := &MyStruct{}
+	err := signedManager.PopulateFromCookies(req, dest)
+
+	var fieldErrs FieldErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) != 1 || fieldErrs[0].Field != "Strict" {
+		t.Fatalf("Expected a single field error for Strict, but got '%v'", err)
+	}
+
+	pageSize := 20
+	expected := &MyStruct{
+		Theme:    "light",
+		Page:     3,
+		PageSize: &pageSize,
+		Tags:     []string{"a", "b"},
+		Token:    "guest",
+	}
+	if !reflect.DeepEqual(dest, expected) {
+		t.Errorf("Unexpected result. Got: %+v, want: %+v", dest, expected)
+	}
+}
+
+func TestPopulateFromCookies_InvalidDefault(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	type MyStruct struct {
+		Field int `cookie:"cookie,default=invalid"`
+	}
+
+	err := unsignedManager.PopulateFromCookies(req, &MyStruct{})
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("Expected FieldError, but got '%v'", err)
+	}
+	if fieldErr.Value != "invalid" {
+		t.Errorf("Expected value '%s', but got '%s'", "invalid", fieldErr.Value)
+	}
+}
@@ -0,0 +1,68 @@
+package cookie
+
+import "strings"
+
+// Cookie name prefixes that make browsers enforce attributes of a cookie.
+const (
+	// SecurePrefix requires a cookie to be Secure.
+	SecurePrefix = "__Secure-"
+
+	// HostPrefix requires a cookie to be Secure, to have no Domain and to have
+	// the Path "/", locking it to the host that set it.
+	HostPrefix = "__Host-"
+)
+
+// HostPrefixed returns name prefixed with HostPrefix.
+func HostPrefixed(name string) string {
+	return HostPrefix + name
+}
+
+// WithPrefixCorrection makes the Manager correct the attributes of cookies
+// whose names start with SecurePrefix or HostPrefix, instead of returning a
+// PrefixError when they break the rules of the prefix. Secure is set, and for
+// HostPrefix the Domain is cleared and the Path set to "/".
+func WithPrefixCorrection() Option {
+	return func(m *Manager) {
+		m.prefixCorrection = true
+	}
+}
+
+// hasPrefix reports whether name starts with prefix, ignoring case as browsers
+// do.
+func hasPrefix(name, prefix string) bool {
+	return len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix)
+}
+
+// checkPrefix returns the options of the named cookie corrected to follow the
+// rules of its prefix if prefix correction is enabled, or a PrefixError if
+// they break them.
+func (m *Manager) checkPrefix(name string, o Options) (Options, error) {
+	var prefix string
+	switch {
+	case hasPrefix(name, HostPrefix):
+		prefix = HostPrefix
+	case hasPrefix(name, SecurePrefix):
+		prefix = SecurePrefix
+	default:
+		return o, nil
+	}
+
+	if m.prefixCorrection {
+		o.Secure = true
+		if prefix == HostPrefix {
+			o.Domain = ""
+			o.Path = "/"
+		}
+		return o, nil
+	}
+
+	switch {
+	case !o.Secure:
+		return o, &PrefixError{Cookie: name, Prefix: prefix, Reason: "must be Secure"}
+	case prefix == HostPrefix && o.Domain != "":
+		return o, &PrefixError{Cookie: name, Prefix: prefix, Reason: "must not have a Domain"}
+	case prefix == HostPrefix && o.Path != "/":
+		return o, &PrefixError{Cookie: name, Prefix: prefix, Reason: `must have the Path "/"`}
+	}
+	return o, nil
+}
@@ -0,0 +1,110 @@
+package cookie
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostPrefixed(t *testing.T) {
+	if name := HostPrefixed("session"); name != "__Host-session" {
+		t.Errorf("Expected '__Host-session', but got '%s'", name)
+	}
+}
+
+func TestManager_Set_Prefix(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		"secure":                {"__Secure-id", Options{Secure: true}, ""},
+		"secure missing secure": {"__Secure-id", Options{}, `cookie: __Secure- cookie "__Secure-id" must be Secure`},
+		"secure partitioned":    {"__Secure-id", Options{Partitioned: true}, ""},
+		"host":                  {"__Host-id", Options{Path: "/", Secure: true}, ""},
+		"host missing secure":   {"__Host-id", Options{Path: "/"}, `cookie: __Host- cookie "__Host-id" must be Secure`},
+		"host domain":           {"__Host-id", Options{Path: "/", Domain: "example.com", Secure: true}, `cookie: __Host- cookie "__Host-id" must not have a Domain`},
+		"host path":             {"__Host-id", Options{Path: "/app", Secure: true}, `cookie: __Host- cookie "__Host-id" must have the Path "/"`},
+		"host no path":          {"__Host-id", Options{Secure: true}, `cookie: __Host- cookie "__Host-id" must have the Path "/"`},
+		"case insensitive":      {"__host-id", Options{Path: "/"}, `cookie: __Host- cookie "__host-id" must be Secure`},
+		"no prefix":             {"id", Options{}, ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := unsignedManager.Set(w, tt.name, "value", tt.opts)
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var prefixErr *PrefixError
+			if !errors.As(err, &prefixErr) {
+				t.Fatalf("Expected PrefixError, but got %v", err)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+			if len(w.Result().Cookies()) != 0 {
+				t.Errorf("Expected no cookies, but got %d", len(w.Result().Cookies()))
+			}
+		})
+	}
+}
+
+func TestManager_SetSigned_Prefix(t *testing.T) {
+	m := NewManager(WithSigningKey([]byte("super-secret-key")))
+
+	var prefixErr *PrefixError
+	err := m.SetSigned(httptest.NewRecorder(), "__Host-id", "value", Options{Secure: true})
+	if !errors.As(err, &prefixErr) {
+		t.Errorf("Expected PrefixError, but got %v", err)
+	}
+}
+
+func TestManager_Remove_Prefix(t *testing.T) {
+	var prefixErr *PrefixError
+	err := unsignedManager.Remove(httptest.NewRecorder(), "__Secure-id")
+	if !errors.As(err, &prefixErr) {
+		t.Errorf("Expected PrefixError, but got %v", err)
+	}
+}
+
+func TestManager_PrefixCorrection(t *testing.T) {
+	m := NewManager(WithSigningKey([]byte("super-secret-key")), WithPrefixCorrection())
+
+	tests := map[string]struct {
+		name   string
+		opts   Options
+		path   string
+		domain string
+	}{
+		"secure": {"__Secure-id", Options{Path: "/app", Domain: "example.com"}, "/app", "example.com"},
+		"host":   {"__Host-id", Options{Path: "/app", Domain: "example.com", Signed: true}, "/", ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := m.Set(w, tt.name, "value", tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := m.Remove(w, tt.name, tt.opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			cookies := w.Result().Cookies()
+			if len(cookies) != 2 {
+				t.Fatalf("Expected 2 cookies, but got %d", len(cookies))
+			}
+			for _, cookie := range cookies {
+				if !cookie.Secure || cookie.Path != tt.path || cookie.Domain != tt.domain {
+					t.Errorf("Expected Secure cookie with path '%s' and domain '%s', but got %+v", tt.path, tt.domain, cookie)
+				}
+			}
+		})
+	}
+}
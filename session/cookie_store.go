@@ -0,0 +1,113 @@
+package session
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/gob"
+	"time"
+)
+
+// cookiePayload is the data encoded by CookieStore.
+type cookiePayload struct {
+	Values    map[string]interface{}
+	ExpiresAt int64
+
+	// Key identifies the session for as long as it lives, while the encoded
+	// payload changes whenever it is saved.
+	Key string
+}
+
+// CookieStore is a Store keeping the whole session in the session cookie, so
+// no backend is needed. Values are serialized with encoding/gob and compressed,
+// then signed or encrypted by the Manager with the keys of its cookie.Manager.
+// Values of custom types must be registered with gob.Register.
+//
+// As the session lives in the cookie, it cannot be revoked by Delete, and
+// Touch does not extend its expiry, which is set whenever it is saved. Sessions
+// too large to fit in a cookie are rejected with ErrCookieTooLarge.
+type CookieStore struct {
+	now func() time.Time
+}
+
+// NewCookieStore creates a new CookieStore.
+func NewCookieStore() *CookieStore {
+	return &CookieStore{now: time.Now}
+}
+
+// Load decodes the values of the session encoded in id.
+func (s *CookieStore) Load(id string) (map[string]interface{}, error) {
+	payload, err := decodePayload(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if !s.now().Before(time.Unix(payload.ExpiresAt, 0)) {
+		return nil, ErrNotFound
+	}
+	return payload.Values, nil
+}
+
+// SessionKey returns the key of the session encoded in id, which stays the
+// same each time the session is saved, or an empty string if id is invalid.
+func (s *CookieStore) SessionKey(id string) string {
+	payload, err := decodePayload(id)
+	if err != nil {
+		return ""
+	}
+	return payload.Key
+}
+
+// decodePayload decodes the payload encoded in id.
+func decodePayload(id string) (cookiePayload, error) {
+	r := flate.NewReader(bytes.NewReader([]byte(id)))
+	defer r.Close()
+
+	var payload cookiePayload
+	err := gob.NewDecoder(r).Decode(&payload)
+	return payload, err
+}
+
+// Save encodes the values of the session, returning them as the new ID. The key
+// of the session is kept from the previous payload, or is the random ID of a
+// new session.
+func (s *CookieStore) Save(id string, values map[string]interface{}, ttl time.Duration) (string, error) {
+	key := id
+	if previous, err := decodePayload(id); err == nil && previous.Key != "" {
+		key = previous.Key
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+
+	payload := cookiePayload{
+		Values:    values,
+		ExpiresAt: s.now().Add(ttl).Unix(),
+		Key:       key,
+	}
+	if err := gob.NewEncoder(w).Encode(payload); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	// Encoding the value for the cookie can only make it larger, so there is
+	// no point in going further if it already exceeds the limit.
+	if buf.Len() > MaxCookieSize {
+		return "", &ErrCookieTooLarge{Size: buf.Len()}
+	}
+	return buf.String(), nil
+}
+
+// Delete does nothing, as the session lives in the cookie. The Manager removes
+// the cookie when the session is destroyed.
+func (s *CookieStore) Delete(id string) error {
+	return nil
+}
+
+// Touch does nothing, as the expiry of the session is encoded in the cookie.
+func (s *CookieStore) Touch(id string, ttl time.Duration) error {
+	return nil
+}
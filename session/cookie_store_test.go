@@ -0,0 +1,192 @@
+package session
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/syntaqx/cookie"
+)
+
+func TestCookieStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewCookieStore()
+	store.now = func() time.Time { return now }
+
+	values := map[string]interface{}{
+		"user":  "alice",
+		"count": 3,
+		"admin": true,
+	}
+
+	id, err := store.Save("", values, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := store.Load(id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for key, value := range values {
+		if loaded[key] != value {
+			t.Errorf("Expected value '%v' for '%s', but got '%v'", value, key, loaded[key])
+		}
+	}
+
+	if err := store.Touch(id, time.Hour); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := store.Delete(id); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := store.Load(id); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestCookieStore_Compresses(t *testing.T) {
+	store := NewCookieStore()
+
+	values := map[string]interface{}{"data": strings.Repeat("compressible ", 500)}
+	id, err := store.Save("", values, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(id) >= len(values["data"].(string)) {
+		t.Errorf("Expected session to be compressed, but got %d bytes", len(id))
+	}
+}
+
+func TestCookieStore_Load_Invalid(t *testing.T) {
+	store := NewCookieStore()
+
+	if _, err := store.Load("invalid"); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestCookieStore_Save_TooLarge(t *testing.T) {
+	store := NewCookieStore()
+
+	values := map[string]interface{}{"data": randomString(t, MaxCookieSize)}
+	_, err := store.Save("", values, time.Hour)
+	if _, ok := err.(*ErrCookieTooLarge); !ok {
+		t.Errorf("Expected ErrCookieTooLarge, but got '%v'", err)
+	}
+}
+
+func TestCookieStore_Save_UnregisteredType(t *testing.T) {
+	store := NewCookieStore()
+
+	type unregistered struct{ Field string }
+	_, err := store.Save("", map[string]interface{}{"data": unregistered{}}, time.Hour)
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+}
+
+func TestManager_CookieStore(t *testing.T) {
+	tests := map[string]struct {
+		cookies *cookie.Manager
+		options cookie.Options
+	}{
+		"signed": {
+			cookies: cookieManager,
+		},
+		"encrypted": {
+			cookies: cookie.NewManager(cookie.WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef"))),
+			options: cookie.Options{Encrypted: true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			manager := NewManager(tt.cookies, NewCookieStore(), WithCookieOptions(tt.options))
+
+			s, _ := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+			s.Set("user", "alice")
+
+			w := httptest.NewRecorder()
+			if err := manager.Save(w, s); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			sessionCookie := w.Result().Cookies()[0]
+			if tt.options.Encrypted && strings.Contains(sessionCookie.Value, "alice") {
+				t.Error("Expected session cookie to be encrypted")
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(sessionCookie)
+
+			loaded, err := manager.Get(r)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if loaded.IsNew() {
+				t.Fatal("Expected an existing session")
+			}
+
+			user, _ := Value[string](loaded, "user")
+			if user != "alice" {
+				t.Errorf("Expected value '%s', but got '%s'", "alice", user)
+			}
+		})
+	}
+}
+
+func TestManager_CookieStore_TooLarge(t *testing.T) {
+	manager := NewManager(cookieManager, NewCookieStore())
+
+	s, _ := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Set("data", randomString(t, MaxCookieSize*7/8))
+
+	w := httptest.NewRecorder()
+	err := manager.Save(w, s)
+	if _, ok := err.(*ErrCookieTooLarge); !ok {
+		t.Errorf("Expected ErrCookieTooLarge, but got '%v'", err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected oversized cookie to not be written")
+	}
+}
+
+// randomString returns an incompressible string of n bytes.
+func randomString(t *testing.T, n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return string(b)
+}
+
+func TestCookieStore_SessionKey(t *testing.T) {
+	store := NewCookieStore()
+
+	id, err := store.Save("random-id", map[string]interface{}{"count": 1}, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key := store.SessionKey(id); key != "random-id" {
+		t.Errorf("Expected key '%s', but got '%s'", "random-id", key)
+	}
+
+	id, err = store.Save(id, map[string]interface{}{"count": 2}, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if key := store.SessionKey(id); key != "random-id" {
+		t.Errorf("Expected key to be kept as '%s', but got '%s'", "random-id", key)
+	}
+
+	if key := store.SessionKey("invalid"); key != "" {
+		t.Errorf("Expected empty key, but got '%s'", key)
+	}
+}
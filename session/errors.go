@@ -0,0 +1,20 @@
+package session
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrNotFound is returned when a session does not exist or has expired.
+var ErrNotFound = errors.New("session not found")
+
+// ErrCookieTooLarge is returned when a session cookie would exceed
+// MaxCookieSize, and be rejected by browsers.
+type ErrCookieTooLarge struct {
+	Size int
+}
+
+// Error returns the error message.
+func (e *ErrCookieTooLarge) Error() string {
+	return "session: cookie of " + strconv.Itoa(e.Size) + " bytes exceeds the " + strconv.Itoa(MaxCookieSize) + " byte limit"
+}
@@ -0,0 +1,12 @@
+package session
+
+import "testing"
+
+func TestErrCookieTooLarge_Error(t *testing.T) {
+	err := &ErrCookieTooLarge{Size: 5000}
+	expected := "session: cookie of 5000 bytes exceeds the 4096 byte limit"
+
+	if err.Error() != expected {
+		t.Errorf("Expected error message '%s', but got '%s'", expected, err.Error())
+	}
+}
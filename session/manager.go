@@ -0,0 +1,233 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/syntaqx/cookie"
+)
+
+// DefaultCookieName is the default name of the session cookie.
+const DefaultCookieName = "session"
+
+// MaxCookieSize is the maximum size in bytes of the name and value of a cookie
+// accepted by browsers.
+const MaxCookieSize = 4096
+
+// DefaultTTL is the default time a session lives without being saved or
+// touched.
+const DefaultTTL = 24 * time.Hour
+
+// Manager loads and saves sessions, issuing their IDs through a cookie.Manager.
+// The cookie.Manager must have a signing key, as session IDs are always sent
+// in a signed cookie, unless the cookie is encrypted instead, in which case it
+// must have an encryption key. Get and Save return cookie.ErrSigningKeyRequired
+// or cookie.ErrEncryptionKeyRequired when the key is missing.
+type Manager struct {
+	cookies    *cookie.Manager
+	store      Store
+	cookieName string
+	ttl        time.Duration
+	options    cookie.Options
+}
+
+// Option is a function type for configuring the Manager.
+type Option func(*Manager)
+
+// WithCookieName sets the name of the session cookie.
+func WithCookieName(name string) Option {
+	return func(m *Manager) {
+		m.cookieName = name
+	}
+}
+
+// WithTTL sets the time a session lives without being saved or touched.
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// WithCookieOptions sets the options of the session cookie. The cookie is
+// always signed, regardless of the Signed option, unless Encrypted is set.
+func WithCookieOptions(opts cookie.Options) Option {
+	return func(m *Manager) {
+		m.options = opts
+	}
+}
+
+// NewManager creates a new Manager storing sessions in store.
+func NewManager(cookies *cookie.Manager, store Store, opts ...Option) *Manager {
+	m := &Manager{
+		cookies:    cookies,
+		store:      store,
+		cookieName: DefaultCookieName,
+		ttl:        DefaultTTL,
+		options: cookie.Options{
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Get returns the session of the request. A new session is returned when the
+// request has no valid session cookie, or its session no longer exists.
+func (m *Manager) Get(r *http.Request) (*Session, error) {
+	if err := m.checkKeys(); err != nil {
+		return nil, err
+	}
+
+	id, err := m.readID(r)
+	if err != nil {
+		return m.newSession()
+	}
+
+	values, err := m.store.Load(id)
+	if err == ErrNotFound {
+		return m.newSession()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSession(id, values, false), nil
+}
+
+// Binding returns a value identifying the session of the request, which stays
+// the same for as long as the session lives, even as it is saved again. It
+// returns an empty string when the request has no valid session cookie. It is
+// meant to bind CSRF tokens to sessions with csrf.WithBinding.
+func (m *Manager) Binding(r *http.Request) string {
+	if m.checkKeys() != nil {
+		return ""
+	}
+	id, err := m.readID(r)
+	if err != nil {
+		return ""
+	}
+	if ks, ok := m.store.(KeyedStore); ok {
+		return ks.SessionKey(id)
+	}
+	return id
+}
+
+// readID returns the verified session ID of the session cookie of the
+// request.
+func (m *Manager) readID(r *http.Request) (string, error) {
+	if m.options.Encrypted {
+		return m.cookies.GetEncrypted(r, m.cookieName)
+	}
+	return m.cookies.GetSigned(r, m.cookieName)
+}
+
+// Save writes the session back to the Store and issues its cookie if it was
+// modified. Unmodified sessions loaded from the Store are touched, extending
+// their expiry, while unmodified new sessions are not persisted at all.
+func (m *Manager) Save(w http.ResponseWriter, s *Session) error {
+	if err := m.checkKeys(); err != nil {
+		return err
+	}
+
+	if !s.dirty {
+		if s.isNew {
+			return nil
+		}
+		return m.store.Touch(s.id, m.ttl)
+	}
+
+	id, err := m.store.Save(s.id, s.values, m.ttl)
+	if err != nil {
+		return err
+	}
+
+	opts := m.options
+	opts.Signed = !opts.Encrypted
+	value, err := m.cookies.Encode(m.cookieName, id, opts)
+	if err != nil {
+		return err
+	}
+	if size := len(m.cookieName) + len(value); size > MaxCookieSize {
+		return &ErrCookieTooLarge{Size: size}
+	}
+
+	s.id = id
+	s.dirty = false
+	s.isNew = false
+
+	return m.cookies.SetEncoded(w, m.cookieName, value, opts)
+}
+
+// Destroy deletes the session from the Store and removes its cookie.
+func (m *Manager) Destroy(w http.ResponseWriter, s *Session) error {
+	if !s.isNew {
+		if err := m.store.Delete(s.id); err != nil {
+			return err
+		}
+	}
+
+	s.values = make(map[string]interface{})
+	s.dirty = false
+	s.isNew = true
+	return m.cookies.Remove(w, m.cookieName, m.options)
+}
+
+// Renew assigns a new ID to the session, keeping its values, and deletes the
+// old one from the Store. It should be called whenever the privilege level of
+// the session changes, such as on login, to prevent session fixation.
+func (m *Manager) Renew(s *Session) error {
+	if !s.isNew {
+		if err := m.store.Delete(s.id); err != nil {
+			return err
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+
+	s.id = id
+	s.dirty = true
+	s.isNew = true
+	return nil
+}
+
+// checkKeys returns an error if the cookie.Manager lacks the key needed to
+// encrypt or sign the session cookie, which would otherwise be written as is
+// and could be forged.
+func (m *Manager) checkKeys() error {
+	if m.options.Encrypted {
+		if !m.cookies.CanEncrypt() {
+			return cookie.ErrEncryptionKeyRequired
+		}
+		return nil
+	}
+	if !m.cookies.CanSign() {
+		return cookie.ErrSigningKeyRequired
+	}
+	return nil
+}
+
+// newSession creates a new, empty session with a random ID.
+func (m *Manager) newSession() (*Session, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	return newSession(id, nil, true), nil
+}
+
+// newID generates a random session ID.
+func newID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,338 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/syntaqx/cookie"
+)
+
+var cookieManager = cookie.NewManager(cookie.WithSigningKey([]byte("super-secret-key")))
+
+// countingStore wraps a Store, counting the calls to each method.
+type countingStore struct {
+	Store
+	saves, touches int
+}
+
+func (s *countingStore) Save(id string, values map[string]interface{}, ttl time.Duration) (string, error) {
+	s.saves++
+	return s.Store.Save(id, values, ttl)
+}
+
+func (s *countingStore) Touch(id string, ttl time.Duration) error {
+	s.touches++
+	return s.Store.Touch(id, ttl)
+}
+
+// failingStore is a Store failing every operation.
+type failingStore struct{}
+
+var errStore = errors.New("store failure")
+
+func (failingStore) Load(string) (map[string]interface{}, error) {
+	return nil, errStore
+}
+
+func (failingStore) Save(string, map[string]interface{}, time.Duration) (string, error) {
+	return "", errStore
+}
+
+func (failingStore) Delete(string) error {
+	return errStore
+}
+
+func (failingStore) Touch(string, time.Duration) error {
+	return errStore
+}
+
+func TestManager_RoundTrip(t *testing.T) {
+	store := &countingStore{Store: NewMemoryStore(0)}
+	manager := NewManager(cookieManager, store)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	s, err := manager.Get(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !s.IsNew() {
+		t.Error("Expected a new session")
+	}
+
+	s.Set("user", "alice")
+
+	w := httptest.NewRecorder()
+	if err := manager.Save(w, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultCookieName {
+		t.Fatalf("Expected session cookie, but got %v", cookies)
+	}
+	if cookies[0].Value == s.ID() {
+		t.Error("Expected session cookie to be signed")
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+
+	loaded, err := manager.Get(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.IsNew() {
+		t.Error("Expected an existing session")
+	}
+	if loaded.ID() != s.ID() {
+		t.Errorf("Expected session ID '%s', but got '%s'", s.ID(), loaded.ID())
+	}
+
+	user, _ := Value[string](loaded, "user")
+	if user != "alice" {
+		t.Errorf("Expected value '%s', but got '%s'", "alice", user)
+	}
+
+	w = httptest.NewRecorder()
+	if err := manager.Save(w, loaded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.saves != 1 || store.touches != 1 {
+		t.Errorf("Expected 1 save and 1 touch, but got %d saves and %d touches", store.saves, store.touches)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected unmodified session to not issue a cookie")
+	}
+}
+
+func TestManager_Save_NewUnmodified(t *testing.T) {
+	store := &countingStore{Store: NewMemoryStore(0)}
+	manager := NewManager(cookieManager, store)
+
+	s, err := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := manager.Save(w, s); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if store.saves != 0 || store.touches != 0 {
+		t.Error("Expected new unmodified session to not be persisted")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected new unmodified session to not issue a cookie")
+	}
+}
+
+func TestManager_Get_InvalidCookie(t *testing.T) {
+	manager := NewManager(cookieManager, NewMemoryStore(0))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "forged"})
+
+	s, err := manager.Get(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !s.IsNew() {
+		t.Error("Expected a new session for a forged cookie")
+	}
+}
+
+func TestManager_Get_Expired(t *testing.T) {
+	manager := NewManager(cookieManager, NewMemoryStore(0))
+
+	w := httptest.NewRecorder()
+	cookieManager.SetSigned(w, DefaultCookieName, "unknown")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	s, err := manager.Get(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !s.IsNew() || s.ID() == "unknown" {
+		t.Error("Expected a new session with a new ID for an unknown session")
+	}
+}
+
+func TestManager_StoreErrors(t *testing.T) {
+	manager := NewManager(cookieManager, failingStore{})
+
+	w := httptest.NewRecorder()
+	cookieManager.SetSigned(w, DefaultCookieName, "id")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	if _, err := manager.Get(r); err != errStore {
+		t.Errorf("Expected error '%v', but got '%v'", errStore, err)
+	}
+
+	s := newSession("id", nil, false)
+	if err := manager.Save(httptest.NewRecorder(), s); err != errStore {
+		t.Errorf("Expected error '%v', but got '%v'", errStore, err)
+	}
+
+	s.Set("user", "alice")
+	if err := manager.Save(httptest.NewRecorder(), s); err != errStore {
+		t.Errorf("Expected error '%v', but got '%v'", errStore, err)
+	}
+
+	if err := manager.Destroy(httptest.NewRecorder(), s); err != errStore {
+		t.Errorf("Expected error '%v', but got '%v'", errStore, err)
+	}
+
+	if err := manager.Renew(s); err != errStore {
+		t.Errorf("Expected error '%v', but got '%v'", errStore, err)
+	}
+}
+
+func TestManager_KeyRequired(t *testing.T) {
+	tests := map[string]struct {
+		cookies  *cookie.Manager
+		options  cookie.Options
+		expected error
+	}{
+		"no signing key": {
+			cookies:  cookie.NewManager(cookie.WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef"))),
+			expected: cookie.ErrSigningKeyRequired,
+		},
+		"no encryption key": {
+			cookies:  cookieManager,
+			options:  cookie.Options{Encrypted: true},
+			expected: cookie.ErrEncryptionKeyRequired,
+		},
+	}
+
+	stores := map[string]func() Store{
+		"memory": func() Store { return NewMemoryStore(0) },
+		"cookie": func() Store { return NewCookieStore() },
+	}
+
+	for name, tt := range tests {
+		for storeName, newStore := range stores {
+			t.Run(name+"/"+storeName, func(t *testing.T) {
+				manager := NewManager(tt.cookies, newStore(), WithCookieOptions(tt.options))
+
+				// A session forged by a client must not be loaded.
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.AddCookie(&http.Cookie{Name: DefaultCookieName, Value: "forged"})
+				if _, err := manager.Get(r); err != tt.expected {
+					t.Errorf("Expected error '%v', but got '%v'", tt.expected, err)
+				}
+
+				s := newSession("id", nil, true)
+				s.Set("user", "admin")
+
+				w := httptest.NewRecorder()
+				if err := manager.Save(w, s); err != tt.expected {
+					t.Errorf("Expected error '%v', but got '%v'", tt.expected, err)
+				}
+				if len(w.Result().Cookies()) != 0 {
+					t.Errorf("Expected no cookies, but got %v", w.Result().Cookies())
+				}
+			})
+		}
+	}
+}
+
+func TestManager_Binding(t *testing.T) {
+	stores := map[string]Store{
+		"memory": NewMemoryStore(0),
+		"cookie": NewCookieStore(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			manager := NewManager(cookieManager, store)
+
+			if binding := manager.Binding(httptest.NewRequest(http.MethodGet, "/", nil)); binding != "" {
+				t.Errorf("Expected empty binding without a session, but got '%s'", binding)
+			}
+
+			var bindings []string
+			s, _ := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+			for i := 0; i < 2; i++ {
+				s.Set("count", i)
+				w := httptest.NewRecorder()
+				if err := manager.Save(w, s); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.AddCookie(w.Result().Cookies()[0])
+				bindings = append(bindings, manager.Binding(r))
+			}
+
+			if bindings[0] == "" || bindings[0] != bindings[1] {
+				t.Errorf("Expected binding to stay the same, but got %q", bindings)
+			}
+		})
+	}
+}
+
+func TestManager_Destroy(t *testing.T) {
+	store := NewMemoryStore(0)
+	manager := NewManager(cookieManager, store, WithCookieName("sid"))
+
+	s, _ := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Set("user", "alice")
+	manager.Save(httptest.NewRecorder(), s)
+	id := s.ID()
+
+	w := httptest.NewRecorder()
+	if err := manager.Destroy(w, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := store.Load(id); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "sid" || cookies[0].MaxAge != -1 {
+		t.Errorf("Expected session cookie to be removed, but got %v", cookies)
+	}
+}
+
+func TestManager_Renew(t *testing.T) {
+	store := NewMemoryStore(0)
+	manager := NewManager(cookieManager, store, WithTTL(time.Minute), WithCookieOptions(cookie.Options{Secure: true}))
+
+	s, _ := manager.Get(httptest.NewRequest(http.MethodGet, "/", nil))
+	s.Set("user", "alice")
+	manager.Save(httptest.NewRecorder(), s)
+	oldID := s.ID()
+
+	if err := manager.Renew(s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.ID() == oldID {
+		t.Error("Expected session ID to change")
+	}
+	if _, err := store.Load(oldID); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := manager.Save(w, s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if !cookie.Secure {
+		t.Error("Expected session cookie to use the configured options")
+	}
+
+	values, err := store.Load(s.ID())
+	if err != nil || values["user"] != "alice" {
+		t.Errorf("Expected renewed session to keep its values, but got %v (%v)", values, err)
+	}
+}
@@ -0,0 +1,134 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryEntry is a session stored by MemoryStore.
+type memoryEntry struct {
+	values    map[string]interface{}
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store keeping sessions in memory. Expired sessions are
+// evicted when accessed, and periodically when a cleanup interval is set.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]memoryEntry
+	now      func() time.Time
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewMemoryStore creates a new MemoryStore. When cleanupInterval is positive,
+// expired sessions are evicted in the background at that interval until Close
+// is called.
+func NewMemoryStore(cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{
+		sessions: make(map[string]memoryEntry),
+		now:      time.Now,
+		done:     make(chan struct{}),
+	}
+	if cleanupInterval > 0 {
+		go s.cleanup(cleanupInterval)
+	}
+	return s
+}
+
+// Load returns the values of the session with the given ID.
+func (s *MemoryStore) Load(id string) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !s.now().Before(entry.expiresAt) {
+		delete(s.sessions, id)
+		return nil, ErrNotFound
+	}
+	return copyValues(entry.values), nil
+}
+
+// Save stores the values of the session with the given ID.
+func (s *MemoryStore) Save(id string, values map[string]interface{}, ttl time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[id] = memoryEntry{
+		values:    copyValues(values),
+		expiresAt: s.now().Add(ttl),
+	}
+	return id, nil
+}
+
+// Delete removes the session with the given ID.
+func (s *MemoryStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
+
+// Touch extends the expiry of the session with the given ID.
+func (s *MemoryStore) Touch(id string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.sessions[id]
+	if !ok || !s.now().Before(entry.expiresAt) {
+		delete(s.sessions, id)
+		return ErrNotFound
+	}
+	entry.expiresAt = s.now().Add(ttl)
+	s.sessions[id] = entry
+	return nil
+}
+
+// Evict removes every expired session.
+func (s *MemoryStore) Evict() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for id, entry := range s.sessions {
+		if !now.Before(entry.expiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// Close stops the background cleanup of expired sessions.
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+// cleanup evicts expired sessions at the given interval until Close is called.
+func (s *MemoryStore) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Evict()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// copyValues returns a shallow copy of the given values.
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
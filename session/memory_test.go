@@ -0,0 +1,117 @@
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(0)
+	store.now = func() time.Time { return now }
+
+	id, err := store.Save("id", map[string]interface{}{"user": "alice"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "id" {
+		t.Errorf("Expected ID '%s', but got '%s'", "id", id)
+	}
+
+	values, err := store.Load("id")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if values["user"] != "alice" {
+		t.Errorf("Expected value '%s', but got '%v'", "alice", values["user"])
+	}
+
+	values["user"] = "bob"
+	values, _ = store.Load("id")
+	if values["user"] != "alice" {
+		t.Error("Expected loaded values to be a copy")
+	}
+
+	now = now.Add(59 * time.Minute)
+	if err := store.Touch("id", time.Hour); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	now = now.Add(59 * time.Minute)
+	if _, err := store.Load("id"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := store.Load("id"); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+	if err := store.Touch("id", time.Hour); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestMemoryStore_Delete(t *testing.T) {
+	store := NewMemoryStore(0)
+
+	if _, err := store.Save("id", nil, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := store.Delete("id"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := store.Load("id"); err != ErrNotFound {
+		t.Errorf("Expected error '%v', but got '%v'", ErrNotFound, err)
+	}
+}
+
+func TestMemoryStore_Evict(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(0)
+	store.now = func() time.Time { return now }
+
+	store.Save("short", nil, time.Minute)
+	store.Save("long", nil, time.Hour)
+
+	now = now.Add(time.Minute)
+	store.Evict()
+
+	if _, ok := store.sessions["short"]; ok {
+		t.Error("Expected expired session to be evicted")
+	}
+	if _, ok := store.sessions["long"]; !ok {
+		t.Error("Expected live session to be kept")
+	}
+}
+
+func TestMemoryStore_Cleanup(t *testing.T) {
+	store := NewMemoryStore(time.Millisecond)
+	defer store.Close()
+
+	store.Save("id", nil, time.Nanosecond)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		store.mu.Lock()
+		n := len(store.sessions)
+		store.mu.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("Expected expired session to be evicted in the background")
+}
+
+func TestMemoryStore_Close(t *testing.T) {
+	store := NewMemoryStore(time.Millisecond)
+
+	if err := store.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
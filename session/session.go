@@ -0,0 +1,83 @@
+// Package session provides server-side sessions on top of cookie.Manager. A
+// session is identified by a random ID issued in a signed cookie, while its
+// values are kept in a pluggable Store.
+package session
+
+// Session is a key/value bag associated with a single client. Changes are
+// tracked, so a session is only written back to its Store when modified.
+type Session struct {
+	id     string
+	values map[string]interface{}
+	dirty  bool
+	isNew  bool
+}
+
+// newSession creates a session with the given ID and values.
+func newSession(id string, values map[string]interface{}, isNew bool) *Session {
+	if values == nil {
+		values = make(map[string]interface{})
+	}
+	return &Session{id: id, values: values, isNew: isNew}
+}
+
+// ID returns the ID of the session.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// IsNew reports whether the session was created by this request, rather than
+// loaded from the Store.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// IsDirty reports whether the session was modified since it was loaded.
+func (s *Session) IsDirty() bool {
+	return s.dirty
+}
+
+// Get returns the value stored under key.
+func (s *Session) Get(key string) (interface{}, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores a value under key.
+func (s *Session) Set(key string, value interface{}) {
+	s.values[key] = value
+	s.dirty = true
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.values[key]; !ok {
+		return
+	}
+	delete(s.values, key)
+	s.dirty = true
+}
+
+// Clear removes every value from the session.
+func (s *Session) Clear() {
+	if len(s.values) == 0 {
+		return
+	}
+	s.values = make(map[string]interface{})
+	s.dirty = true
+}
+
+// Keys returns the keys of every value in the session.
+func (s *Session) Keys() []string {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Value returns the value stored under key as type T. It reports false if the
+// key is not set or holds a value of another type.
+func Value[T any](s *Session, key string) (T, bool) {
+	value, ok := s.values[key].(T)
+	return value, ok
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestSession_SetGetDelete(t *testing.T) {
+	s := newSession("id", nil, true)
+
+	if s.IsDirty() {
+		t.Error("Expected new session to be clean")
+	}
+
+	s.Set("user", "alice")
+	if !s.IsDirty() {
+		t.Error("Expected session to be dirty after Set")
+	}
+
+	value, ok := s.Get("user")
+	if !ok || value != "alice" {
+		t.Errorf("Expected value '%s', but got '%v'", "alice", value)
+	}
+
+	s.dirty = false
+	s.Delete("missing")
+	if s.IsDirty() {
+		t.Error("Expected session to be clean after deleting a missing key")
+	}
+
+	s.Delete("user")
+	if !s.IsDirty() {
+		t.Error("Expected session to be dirty after Delete")
+	}
+
+	if _, ok := s.Get("user"); ok {
+		t.Error("Expected value to be deleted")
+	}
+}
+
+func TestSession_Clear(t *testing.T) {
+	s := newSession("id", map[string]interface{}{"a": 1, "b": 2}, false)
+
+	s.Clear()
+	if !s.IsDirty() {
+		t.Error("Expected session to be dirty after Clear")
+	}
+	if len(s.Keys()) != 0 {
+		t.Errorf("Expected no keys, but got %v", s.Keys())
+	}
+
+	s.dirty = false
+	s.Clear()
+	if s.IsDirty() {
+		t.Error("Expected session to be clean after clearing an empty session")
+	}
+}
+
+func TestSession_Keys(t *testing.T) {
+	s := newSession("id", map[string]interface{}{"b": 1, "a": 2}, false)
+
+	keys := s.Keys()
+	sort.Strings(keys)
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Errorf("Expected keys [a b], but got %v", keys)
+	}
+}
+
+func TestValue(t *testing.T) {
+	s := newSession("id", map[string]interface{}{"count": 3, "name": "alice"}, false)
+
+	count, ok := Value[int](s, "count")
+	if !ok || count != 3 {
+		t.Errorf("Expected value %d, but got %d", 3, count)
+	}
+
+	_, ok = Value[string](s, "count")
+	if ok {
+		t.Error("Expected value of the wrong type to not be returned")
+	}
+
+	_, ok = Value[string](s, "missing")
+	if ok {
+		t.Error("Expected missing value to not be returned")
+	}
+}
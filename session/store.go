@@ -0,0 +1,32 @@
+package session
+
+import "time"
+
+// Store persists session values between requests.
+type Store interface {
+	// Load returns the values of the session with the given ID, or ErrNotFound
+	// if it does not exist or has expired.
+	Load(id string) (map[string]interface{}, error)
+
+	// Save stores the values of the session with the given ID, expiring them
+	// after ttl. It returns the ID to issue to the client, which is normally id
+	// itself, but may differ for stores that encode the values in the ID.
+	Save(id string, values map[string]interface{}, ttl time.Duration) (string, error)
+
+	// Delete removes the session with the given ID.
+	Delete(id string) error
+
+	// Touch extends the expiry of the session with the given ID to ttl from
+	// now, without modifying its values.
+	Touch(id string, ttl time.Duration) error
+}
+
+// KeyedStore is implemented by Stores whose IDs change whenever a session is
+// saved, such as CookieStore, to identify sessions for as long as they live.
+type KeyedStore interface {
+	Store
+
+	// SessionKey returns the key of the session with the given ID, or an
+	// empty string if the ID is invalid.
+	SessionKey(id string) string
+}
@@ -1,27 +1,146 @@
 package cookie
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // sign generates a HMAC signature for the given data using the provided key.
 func sign(data, key []byte) []byte {
-	h := hmac.New(sha256.New, key)
-	h.Write(data)
-	return h.Sum(nil)
+	signature, _ := NewHMACSHA256Signer(key).Sign(data)
+	return signature
 }
 
 // verify checks the HMAC signature of the given data using the provided key.
 func verify(data, signature, key []byte) bool {
-	expectedSignature := sign(data, key)
-	return hmac.Equal(expectedSignature, signature)
+	return NewHMACSHA256Signer(key).Verify(data, signature)
 }
 
 // signCookieValue signs a cookie value using the provided key.
 func signCookieValue(value string, key []byte) string {
+	signed, _ := signCookieValueWith(value, "", "", NewHMACSHA256Signer(key), time.Time{})
+	return signed
+}
+
+// signCookieValueWith signs a cookie value using the provided signer. The
+// context is signed along with the value, but not included in the result. When
+// id is not empty, the signature is prefixed with it so the signer can be
+// looked up when verifying. When issuedAt is not zero, it is embedded in the
+// signed data so the age of the signature can be checked.
+func signCookieValueWith(value, context, id string, signer Signer, issuedAt time.Time) (string, error) {
 	data := base64.URLEncoding.EncodeToString([]byte(value))
-	signature := base64.URLEncoding.EncodeToString(sign([]byte(data), key))
-	return data + "|" + signature
+	if !issuedAt.IsZero() {
+		data += "." + strconv.FormatInt(issuedAt.Unix(), 10)
+	}
+	signature, err := signer.Sign([]byte(context + data))
+	if err != nil {
+		return "", err
+	}
+
+	encodedSignature := base64.URLEncoding.EncodeToString(signature)
+	if id != "" {
+		encodedSignature = id + "." + encodedSignature
+	}
+	return data + "|" + encodedSignature, nil
+}
+
+// CanSign reports whether the Manager has a key to sign cookies with. Without
+// one, Set returns ErrSigningKeyRequired for values meant to be signed.
+func (m *Manager) CanSign() bool {
+	return m.primaryKeyID != "" || m.signer != nil || len(m.signingKey) > 0
+}
+
+// defaultSigner returns the signer used for signatures without a key ID, or
+// nil when neither WithSigner nor WithSigningKey was used.
+func (m *Manager) defaultSigner() Signer {
+	if m.signer != nil {
+		return m.signer
+	}
+	if len(m.signingKey) == 0 {
+		return nil
+	}
+	return NewHMACSHA256Signer(m.signingKey)
+}
+
+// signatureContext returns the context signed along with the value of the
+// named cookie, binding the signature to the cookie name and purpose when
+// enabled by WithBoundSignatures.
+func (m *Manager) signatureContext(name string) string {
+	if !m.boundSignatures {
+		return ""
+	}
+	return name + "|" + m.signaturePurpose + "|"
+}
+
+// signValue signs the value of the named cookie with the primary signer of the
+// keyring, falling back to the default signer when no keyring is configured.
+func (m *Manager) signValue(name, value string) (string, error) {
+	var issuedAt time.Time
+	if m.timestamped {
+		issuedAt = m.clock()
+	}
+	context := m.signatureContext(name)
+	if m.primaryKeyID != "" {
+		return signCookieValueWith(value, context, m.primaryKeyID, m.keyring[m.primaryKeyID], issuedAt)
+	}
+	signer := m.defaultSigner()
+	if signer == nil {
+		return "", ErrSigningKeyRequired
+	}
+	return signCookieValueWith(value, context, "", signer, issuedAt)
+}
+
+// verifyValue verifies the signed value of the named cookie and returns the
+// original value. When maxAge is positive, the value must carry an issued-at
+// timestamp no older than maxAge.
+func (m *Manager) verifyValue(name, value string, maxAge time.Duration) (string, error) {
+	parts := strings.Split(value, "|")
+	if len(parts) != 2 {
+		return "", ErrInvalidSignedCookieFormat
+	}
+
+	data, signature := parts[0], parts[1]
+	signer := m.defaultSigner()
+	if id, sig, ok := strings.Cut(signature, "."); ok {
+		s, found := m.keyring[id]
+		if !found {
+			return "", ErrUnknownSigningKey
+		}
+		signer, signature = s, sig
+	}
+	if signer == nil {
+		return "", ErrInvalidCookieSignature
+	}
+
+	payload, timestamp, timestamped := strings.Cut(data, ".")
+	dataBytes, err := base64.URLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", err
+	}
+	signatureBytes, err := base64.URLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", err
+	}
+
+	if !signer.Verify([]byte(m.signatureContext(name)+data), signatureBytes) {
+		if !m.boundSignatures || !m.acceptUnboundSignatures || !signer.Verify([]byte(data), signatureBytes) {
+			return "", ErrInvalidCookieSignature
+		}
+	}
+
+	if maxAge > 0 {
+		if !timestamped {
+			return "", ErrSignatureExpired
+		}
+		issuedAt, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return "", ErrInvalidSignedCookieFormat
+		}
+		if m.clock().Sub(time.Unix(issuedAt, 0)) > maxAge {
+			return "", ErrSignatureExpired
+		}
+	}
+	return string(dataBytes), nil
 }
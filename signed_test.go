@@ -2,7 +2,14 @@ package cookie
 
 import (
 	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSignVerify(t *testing.T) {
@@ -24,3 +31,294 @@ func TestSignVerify(t *testing.T) {
 		t.Error("verify failed to validate the signature")
 	}
 }
+
+func TestManager_Keyring(t *testing.T) {
+	oldManager := NewManager(WithKeyring("v1", map[string][]byte{
+		"v1": []byte("old-secret-key"),
+	}))
+	newManager := NewManager(WithKeyring("v2", map[string][]byte{
+		"v1": []byte("old-secret-key"),
+		"v2": []byte("new-secret-key"),
+	}))
+
+	oldValue, err := oldManager.signValue("myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(oldValue, "|v1.") {
+		t.Errorf("Expected signature to carry key ID 'v1', but got '%s'", oldValue)
+	}
+
+	value, err := newManager.verifyValue("myCookie", oldValue, 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+
+	newValue, err := newManager.signValue("myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(newValue, "|v2.") {
+		t.Errorf("Expected signature to carry key ID 'v2', but got '%s'", newValue)
+	}
+
+	_, err = oldManager.verifyValue("myCookie", newValue, 0)
+	if err != ErrUnknownSigningKey {
+		t.Errorf("Expected error '%v', but got '%v'", ErrUnknownSigningKey, err)
+	}
+}
+
+func TestManager_Keyring_LegacySignature(t *testing.T) {
+	manager := NewManager(
+		WithSigningKey([]byte("legacy-secret-key")),
+		WithKeyring("v1", map[string][]byte{"v1": []byte("new-secret-key")}),
+	)
+
+	value, err := manager.verifyValue("myCookie", signCookieValue("myValue", []byte("legacy-secret-key")), 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+}
+
+func TestManager_Keyring_InvalidSignature(t *testing.T) {
+	manager := NewManager(WithKeyring("v1", map[string][]byte{"v1": []byte("secret-key")}))
+
+	value, err := signCookieValueWith("myValue", "", "v1", NewHMACSHA256Signer([]byte("wrong-key")), time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = manager.verifyValue("myCookie", value, 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
+
+func TestManager_Keyring_UnkeyedSignatureWithoutSigningKey(t *testing.T) {
+	manager := NewManager(WithKeyring("v1", map[string][]byte{"v1": []byte("secret-key")}))
+
+	// An HMAC with an empty key must not be accepted in place of a signature
+	// by the keyring.
+	forged, _ := signCookieValueWith("admin", "", "", &hmacSigner{hash: sha256.New}, time.Time{})
+
+	_, err := manager.verifyValue("myCookie", forged, 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
+
+func TestManager_UnkeyedSignatureWithoutKey(t *testing.T) {
+	forged, _ := signCookieValueWith("admin", "", "", &hmacSigner{hash: sha256.New}, time.Time{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "myCookie", Value: forged})
+
+	_, err := unsignedManager.GetSigned(req, "myCookie")
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+	if _, err := unsignedManager.signValue("myCookie", "admin"); err != ErrSigningKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningKeyRequired, err)
+	}
+}
+
+func TestManager_CanSign(t *testing.T) {
+	tests := map[string]struct {
+		manager  *Manager
+		expected bool
+	}{
+		"no key":      {unsignedManager, false},
+		"empty key":   {NewManager(WithSigningKey([]byte{})), false},
+		"signing key": {NewManager(WithSigningKey([]byte("super-secret-key"))), true},
+		"keyring":     {NewManager(WithKeyring("v1", map[string][]byte{"v1": []byte("secret-key")})), true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.manager.CanSign() != tt.expected {
+				t.Errorf("Expected %v, but got %v", tt.expected, tt.manager.CanSign())
+			}
+		})
+	}
+}
+
+func TestWithKeyring_Panics(t *testing.T) {
+	tests := map[string]struct {
+		primary string
+		keys    map[string][]byte
+	}{
+		"missing primary": {primary: "v2", keys: map[string][]byte{"v1": []byte("key")}},
+		"empty ID":        {primary: "v1", keys: map[string][]byte{"v1": []byte("key"), "": []byte("key")}},
+		"invalid ID":      {primary: "v.1", keys: map[string][]byte{"v.1": []byte("key")}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic, but got none")
+				}
+			}()
+			WithKeyring(tt.primary, tt.keys)
+		})
+	}
+}
+
+func TestManager_SignatureMaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithSignatureMaxAge(time.Hour),
+		WithClock(func() time.Time { return now }),
+	)
+
+	signed, err := manager.signValue("myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedPrefix := base64.URLEncoding.EncodeToString([]byte("myValue")) + "." + strconv.FormatInt(now.Unix(), 10) + "|"
+	if !strings.HasPrefix(signed, expectedPrefix) {
+		t.Errorf("Expected signed value to start with '%s', but got '%s'", expectedPrefix, signed)
+	}
+
+	now = now.Add(time.Hour)
+	value, err := manager.verifyValue("myCookie", signed, manager.maxAge)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+
+	now = now.Add(time.Second)
+	_, err = manager.verifyValue("myCookie", signed, manager.maxAge)
+	if err != ErrSignatureExpired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSignatureExpired, err)
+	}
+
+	value, err = manager.verifyValue("myCookie", signed, 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+}
+
+func TestManager_SignatureMaxAge_NoTimestamp(t *testing.T) {
+	manager := NewManager(WithSigningKey([]byte("super-secret-key")))
+
+	_, err := manager.verifyValue("myCookie", signCookieValue("myValue", manager.signingKey), time.Hour)
+	if err != ErrSignatureExpired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSignatureExpired, err)
+	}
+}
+
+func TestManager_SignatureMaxAge_InvalidTimestamp(t *testing.T) {
+	manager := NewManager(WithSigningKey([]byte("super-secret-key")))
+
+	data := base64.URLEncoding.EncodeToString([]byte("myValue")) + ".invalid"
+	signature := base64.URLEncoding.EncodeToString(sign([]byte(data), manager.signingKey))
+
+	_, err := manager.verifyValue("myCookie", data+"|"+signature, time.Hour)
+	if err != ErrInvalidSignedCookieFormat {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidSignedCookieFormat, err)
+	}
+}
+
+func TestManager_SignatureMaxAge_TamperedTimestamp(t *testing.T) {
+	now := time.Now()
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithSignatureMaxAge(time.Hour),
+		WithClock(func() time.Time { return now }),
+	)
+
+	signed, err := manager.signValue("myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	issuedAt := strconv.FormatInt(now.Unix(), 10)
+	tampered := strings.Replace(signed, issuedAt, strconv.FormatInt(now.Add(time.Hour).Unix(), 10), 1)
+
+	_, err = manager.verifyValue("myCookie", tampered, time.Hour)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
+
+func TestManager_BoundSignatures(t *testing.T) {
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithBoundSignatures("auth"),
+	)
+
+	signed, err := manager.signValue("User-ID", "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	value, err := manager.verifyValue("User-ID", signed, 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "12345" {
+		t.Errorf("Expected value '%s', but got '%s'", "12345", value)
+	}
+
+	_, err = manager.verifyValue("Is-Admin", signed, 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+
+	otherPurpose := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithBoundSignatures("csrf"),
+	)
+	_, err = otherPurpose.verifyValue("User-ID", signed, 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+
+	_, err = manager.verifyValue("User-ID", signCookieValue("12345", manager.signingKey), 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
+
+func TestManager_BoundSignatures_UnboundAccepted(t *testing.T) {
+	manager := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithBoundSignatures(""),
+		WithUnboundSignaturesAccepted(),
+	)
+
+	value, err := manager.verifyValue("User-ID", signCookieValue("12345", manager.signingKey), 0)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if value != "12345" {
+		t.Errorf("Expected value '%s', but got '%s'", "12345", value)
+	}
+
+	signed, err := manager.signValue("User-ID", "12345")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if signed == signCookieValue("12345", manager.signingKey) {
+		t.Error("Expected new signatures to be bound to the cookie name")
+	}
+
+	_, err = manager.verifyValue("Is-Admin", signed, 0)
+	if err != ErrInvalidCookieSignature {
+		t.Errorf("Expected error '%v', but got '%v'", ErrInvalidCookieSignature, err)
+	}
+}
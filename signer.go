@@ -0,0 +1,104 @@
+package cookie
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"hash"
+)
+
+// Signer signs cookie values and verifies their signatures.
+type Signer interface {
+	// Sign returns the signature of data.
+	Sign(data []byte) ([]byte, error)
+
+	// Verify reports whether signature is a valid signature of data.
+	Verify(data, signature []byte) bool
+}
+
+// hmacSigner is a Signer using HMAC with the given hash function.
+type hmacSigner struct {
+	hash func() hash.Hash
+	key  []byte
+}
+
+// newHMACSigner returns a Signer using HMAC with the given hash function and
+// key, which must not be empty.
+func newHMACSigner(hash func() hash.Hash, key []byte) Signer {
+	if len(key) == 0 {
+		panic("cookie: HMAC signing key must not be empty")
+	}
+	return &hmacSigner{hash: hash, key: key}
+}
+
+// NewHMACSHA256Signer returns a Signer using HMAC-SHA256 with the given key.
+// This is the algorithm used by WithSigningKey and WithKeyring. It panics if
+// the key is empty.
+func NewHMACSHA256Signer(key []byte) Signer {
+	return newHMACSigner(sha256.New, key)
+}
+
+// NewHMACSHA512Signer returns a Signer using HMAC-SHA512 with the given key.
+// It panics if the key is empty.
+func NewHMACSHA512Signer(key []byte) Signer {
+	return newHMACSigner(sha512.New, key)
+}
+
+// NewHMACSHA3Signer returns a Signer using HMAC-SHA3-256 with the given key.
+// It provides keyed hashing from the SHA-3 family for those who would
+// otherwise reach for BLAKE2b, which is not part of the standard library. It
+// panics if the key is empty.
+func NewHMACSHA3Signer(key []byte) Signer {
+	return newHMACSigner(func() hash.Hash { return sha3.New256() }, key)
+}
+
+// Sign returns the HMAC of data.
+func (s *hmacSigner) Sign(data []byte) ([]byte, error) {
+	h := hmac.New(s.hash, s.key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// Verify reports whether signature is the HMAC of data.
+func (s *hmacSigner) Verify(data, signature []byte) bool {
+	expectedSignature, _ := s.Sign(data)
+	return hmac.Equal(expectedSignature, signature)
+}
+
+// ed25519Signer is a Signer using Ed25519. When the private key is nil, it can
+// only verify signatures.
+type ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+// NewEd25519Signer returns a Signer using Ed25519 with the given private key.
+func NewEd25519Signer(key ed25519.PrivateKey) Signer {
+	return &ed25519Signer{
+		privateKey: key,
+		publicKey:  key.Public().(ed25519.PublicKey),
+	}
+}
+
+// NewEd25519Verifier returns a Signer using Ed25519 that can only verify
+// signatures with the given public key. This allows services to accept cookies
+// signed elsewhere without holding the private key. Its Sign method always
+// returns ErrSigningNotSupported.
+func NewEd25519Verifier(key ed25519.PublicKey) Signer {
+	return &ed25519Signer{publicKey: key}
+}
+
+// Sign returns the Ed25519 signature of data.
+func (s *ed25519Signer) Sign(data []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, ErrSigningNotSupported
+	}
+	return ed25519.Sign(s.privateKey, data), nil
+}
+
+// Verify reports whether signature is a valid Ed25519 signature of data.
+func (s *ed25519Signer) Verify(data, signature []byte) bool {
+	return ed25519.Verify(s.publicKey, data, signature)
+}
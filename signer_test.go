@@ -0,0 +1,159 @@
+package cookie
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSigners(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		signer        Signer
+		signatureSize int
+	}{
+		"HMAC-SHA256":   {signer: NewHMACSHA256Signer([]byte("example key")), signatureSize: 32},
+		"HMAC-SHA512":   {signer: NewHMACSHA512Signer([]byte("example key")), signatureSize: 64},
+		"HMAC-SHA3-256": {signer: NewHMACSHA3Signer([]byte("example key")), signatureSize: 32},
+		"Ed25519":       {signer: NewEd25519Signer(privateKey), signatureSize: ed25519.SignatureSize},
+	}
+
+	data := []byte("example data")
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			signature, err := tt.signer.Sign(data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(signature) != tt.signatureSize {
+				t.Errorf("Expected signature size %d, but got %d", tt.signatureSize, len(signature))
+			}
+
+			if !tt.signer.Verify(data, signature) {
+				t.Error("Verify failed to validate the signature")
+			}
+
+			if tt.signer.Verify([]byte("other data"), signature) {
+				t.Error("Verify validated the signature of different data")
+			}
+		})
+	}
+
+	if !NewEd25519Verifier(publicKey).Verify(data, ed25519.Sign(privateKey, data)) {
+		t.Error("Verify failed to validate the signature with the public key")
+	}
+}
+
+func TestHMACSigners_EmptyKey(t *testing.T) {
+	constructors := map[string]func([]byte) Signer{
+		"HMAC-SHA256":   NewHMACSHA256Signer,
+		"HMAC-SHA512":   NewHMACSHA512Signer,
+		"HMAC-SHA3-256": NewHMACSHA3Signer,
+	}
+
+	for name, newSigner := range constructors {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected panic, but got none")
+				}
+			}()
+			newSigner(nil)
+		})
+	}
+}
+
+func TestHMACSHA256Signer_MatchesSign(t *testing.T) {
+	data := []byte("example data")
+	key := []byte("example key")
+
+	signature, err := NewHMACSHA256Signer(key).Sign(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !verify(data, signature, key) {
+		t.Error("HMAC-SHA256 signer does not match the default signature")
+	}
+}
+
+func TestEd25519Verifier_Sign(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = NewEd25519Verifier(publicKey).Sign([]byte("example data"))
+	if err != ErrSigningNotSupported {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningNotSupported, err)
+	}
+}
+
+func TestManager_WithSigner_Ed25519(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	central := NewManager(WithSigner(NewEd25519Signer(privateKey)))
+	edge := NewManager(WithSigner(NewEd25519Verifier(publicKey)))
+
+	w := httptest.NewRecorder()
+	err = central.SetSigned(w, "myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+
+	value, err := edge.GetSigned(r, "myCookie")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if value != "myValue" {
+		t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+	}
+
+	err = edge.SetSigned(httptest.NewRecorder(), "myCookie", "myValue")
+	if err != ErrSigningNotSupported {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningNotSupported, err)
+	}
+}
+
+func TestManager_WithSignerKeyring(t *testing.T) {
+	manager := NewManager(WithSignerKeyring("v2", map[string]Signer{
+		"v1": NewHMACSHA256Signer([]byte("old-secret-key")),
+		"v2": NewHMACSHA512Signer([]byte("new-secret-key")),
+	}))
+
+	oldValue, err := signCookieValueWith("myValue", "", "v1", NewHMACSHA256Signer([]byte("old-secret-key")), time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	newValue, err := manager.signValue("myCookie", "myValue")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, signed := range []string{oldValue, newValue} {
+		value, err := manager.verifyValue("myCookie", signed, 0)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		if value != "myValue" {
+			t.Errorf("Expected value '%s', but got '%s'", "myValue", value)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cookie
+
+import "strings"
+
+// tagOptions represents the options parsed from a `cookie` struct tag.
+type tagOptions struct {
+	name      string
+	signed    bool
+	unsigned  bool
+	encrypted bool
+	omitempty bool
+	inline    bool
+	json      bool
+	sep       string
+
+	// def is the value used when the cookie is absent, if hasDefault is set.
+	// With fallback, it is also used when the cookie fails verification.
+	def        string
+	hasDefault bool
+	fallback   bool
+
+	// rules are the validation rules checked once the field is populated.
+	rules []tagRule
+}
+
+// parseTag parses a `cookie` struct tag into its options. A default= option
+// takes the rest of the tag as its value, commas included, so it must come
+// last. Any other option is kept as a validation rule, which compileFields
+// checks is known.
+func parseTag(tag string) tagOptions {
+	parts := strings.Split(tag, ",")
+	opts := tagOptions{name: parts[0]}
+
+	for i, part := range parts[1:] {
+		if def, ok := strings.CutPrefix(part, "default="); ok {
+			opts.def = strings.Join(append([]string{def}, parts[i+2:]...), ",")
+			opts.hasDefault = true
+			break
+		}
+
+		switch part {
+		case "signed":
+			opts.signed = true
+		case "unsigned":
+			opts.unsigned = true
+		case "encrypted":
+			opts.encrypted = true
+		case "omitempty":
+			opts.omitempty = true
+		case "inline":
+			opts.inline = true
+		case "fallback":
+			opts.fallback = true
+		case "json":
+			opts.json = true
+		default:
+			if sep, ok := strings.CutPrefix(part, "sep="); ok {
+				opts.sep = sep
+			} else if part != "" {
+				name, param, _ := strings.Cut(part, "=")
+				opts.rules = append(opts.rules, tagRule{name: name, param: param})
+			}
+		}
+	}
+	return opts
+}
+
+// separator returns the separator of slice elements and map entries, which
+// defaults to a comma.
+func (o tagOptions) separator() string {
+	if o.sep == "" {
+		return ","
+	}
+	return o.sep
+}
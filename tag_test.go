@@ -0,0 +1,45 @@
+package cookie
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := map[string]tagOptions{
+		"cookie":                     {name: "cookie"},
+		"cookie,signed":              {name: "cookie", signed: true},
+		"cookie,unsigned":            {name: "cookie", unsigned: true},
+		"cookie,encrypted":           {name: "cookie", encrypted: true},
+		"cookie,signed,omitempty":    {name: "cookie", signed: true, omitempty: true},
+		"cookie,unknown,omitempty":   {name: "cookie", omitempty: true, rules: []tagRule{{name: "unknown"}}},
+		"cookie,required,min=1":      {name: "cookie", rules: []tagRule{{name: "required"}, {name: "min", param: "1"}}},
+		"cookie,encrypted,omitempty": {name: "cookie", encrypted: true, omitempty: true},
+		"prefix_,inline":             {name: "prefix_", inline: true},
+		"cookie,sep=|":               {name: "cookie", sep: "|"},
+		"cookie,default=light":       {name: "cookie", def: "light", hasDefault: true},
+		"cookie,default=":            {name: "cookie", hasDefault: true},
+		"cookie,default=a,b,c":       {name: "cookie", def: "a,b,c", hasDefault: true},
+		"cookie,sep=|,default=a|b":   {name: "cookie", sep: "|", def: "a|b", hasDefault: true},
+		"cookie,json,signed":         {name: "cookie", json: true, signed: true},
+		"cookie,signed,fallback":     {name: "cookie", signed: true, fallback: true},
+	}
+
+	for tag, expected := range tests {
+		t.Run(tag, func(t *testing.T) {
+			opts := parseTag(tag)
+			if !reflect.DeepEqual(opts, expected) {
+				t.Errorf("Unexpected result. Got: %+v, want: %+v", opts, expected)
+			}
+		})
+	}
+}
+
+func TestTagOptions_Separator(t *testing.T) {
+	if sep := (tagOptions{}).separator(); sep != "," {
+		t.Errorf("Expected default separator ',', but got '%s'", sep)
+	}
+	if sep := (tagOptions{sep: "|"}).separator(); sep != "|" {
+		t.Errorf("Expected separator '|', but got '%s'", sep)
+	}
+}
@@ -0,0 +1,177 @@
+package cookie
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Validator validates the value of a populated struct field against the
+// parameter of its rule, such as "fr" for `cookie:"LANG,locale=fr"`. The value
+// is the field value, dereferenced if it is a pointer. A non-nil error fails
+// the validation.
+type Validator func(value interface{}, param string) error
+
+// WithValidator registers a named validator for the Manager, which is applied
+// to fields tagged with its name, optionally followed by =param. Built-in
+// rules take precedence over validators with the same name.
+func WithValidator(name string, validator Validator) Option {
+	return func(m *Manager) {
+		m.validators[name] = validator
+	}
+}
+
+// tagRule is a validation rule of a `cookie` struct tag, such as min=1.
+type tagRule struct {
+	name  string
+	param string
+
+	// re is the compiled param of a pattern rule, set by compileFields.
+	re *regexp.Regexp
+}
+
+// String returns the rule as written in the tag.
+func (r tagRule) String() string {
+	if r.param == "" {
+		return r.name
+	}
+	return r.name + "=" + r.param
+}
+
+// isBuiltinRule reports whether name is a built-in validation rule.
+func isBuiltinRule(name string) bool {
+	switch name {
+	case "required", "min", "max", "len", "oneof", "pattern":
+		return true
+	}
+	return false
+}
+
+// findRule returns the rule with the given name, if any.
+func findRule(rules []tagRule, name string) (tagRule, bool) {
+	for _, rule := range rules {
+		if rule.name == name {
+			return rule, true
+		}
+	}
+	return tagRule{}, false
+}
+
+// validateField checks the value of a populated struct field against the
+// validation rules of its tag, which compileFields has checked are either built
+// in or registered with WithValidator. Slice elements and map entries are
+// separated by sep.
+func (m *Manager) validateField(fieldVal reflect.Value, rules []tagRule, sep string) error {
+	if fieldVal.Kind() == reflect.Pointer {
+		if fieldVal.IsNil() {
+			if rule, ok := findRule(rules, "required"); ok {
+				return &ValidationError{Rule: rule.String()}
+			}
+			return nil
+		}
+		fieldVal = fieldVal.Elem()
+	}
+
+	for _, rule := range rules {
+		var err error
+		switch rule.name {
+		case "required":
+			if fieldVal.IsZero() {
+				err = &ValidationError{Rule: rule.String()}
+			}
+		case "min", "max", "len":
+			err = validateBound(fieldVal, rule)
+		case "oneof":
+			err = m.validateEach(fieldVal, rule, sep, func(value string) bool {
+				for _, allowed := range strings.Fields(rule.param) {
+					if value == allowed {
+						return true
+					}
+				}
+				return false
+			})
+		case "pattern":
+			err = m.validateEach(fieldVal, rule, sep, rule.re.MatchString)
+		default:
+			if cause := m.validators[rule.name](fieldVal.Interface(), rule.param); cause != nil {
+				err = &ValidationError{Rule: rule.String(), Err: cause}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBound checks a min, max or len rule. Numbers are compared by value,
+// while strings, slices and maps are compared by length.
+func validateBound(fieldVal reflect.Value, rule tagRule) error {
+	var actual float64
+	switch fieldVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rule.name == "len" {
+			return &ErrUnsupportedType{Type: fieldVal.Type()}
+		}
+		actual = float64(fieldVal.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rule.name == "len" {
+			return &ErrUnsupportedType{Type: fieldVal.Type()}
+		}
+		actual = float64(fieldVal.Uint())
+	case reflect.Float32, reflect.Float64:
+		if rule.name == "len" {
+			return &ErrUnsupportedType{Type: fieldVal.Type()}
+		}
+		actual = fieldVal.Float()
+	case reflect.String:
+		actual = float64(len([]rune(fieldVal.String())))
+	case reflect.Slice, reflect.Map:
+		actual = float64(fieldVal.Len())
+	default:
+		return &ErrUnsupportedType{Type: fieldVal.Type()}
+	}
+
+	bound, err := strconv.ParseFloat(rule.param, 64)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	switch rule.name {
+	case "min":
+		ok = actual >= bound
+	case "max":
+		ok = actual <= bound
+	case "len":
+		ok = actual == bound
+	}
+	if !ok {
+		return &ValidationError{Rule: rule.String()}
+	}
+	return nil
+}
+
+// validateEach checks the formatted value of a field with match, or of each
+// element if the field is a slice.
+func (m *Manager) validateEach(fieldVal reflect.Value, rule tagRule, sep string, match func(string) bool) error {
+	values := []reflect.Value{fieldVal}
+	if fieldVal.Kind() == reflect.Slice {
+		values = make([]reflect.Value, fieldVal.Len())
+		for i := range values {
+			values[i] = fieldVal.Index(i)
+		}
+	}
+
+	for _, v := range values {
+		value, err := m.formatFieldValue(v, sep)
+		if err != nil {
+			return err
+		}
+		if !match(value) {
+			return &ValidationError{Rule: rule.String()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package cookie
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPopulateFromCookies_Validation(t *testing.T) {
+	type MyStruct struct {
+		Theme    string   `cookie:"theme,oneof=light dark"`
+		PageSize int      `cookie:"page_size,min=10,max=100"`
+		Locale   string   `cookie:"locale,pattern=^[a-z]{2}(-[A-Z]{2})?$"`
+		Code     string   `cookie:"code,len=4"`
+		Name     string   `cookie:"name,required"`
+		Tags     []string `cookie:"tags,max=2,oneof=a b c"`
+		Ratio    *float64 `cookie:"ratio,min=0.5"`
+	}
+
+	valid := map[string]string{
+		"theme":     "dark",
+		"page_size": "10",
+		"locale":    "en-US",
+		"code":      "abcd",
+		"name":      "gopher",
+		"tags":      "a,c",
+		"ratio":     "0.5",
+	}
+
+	tests := map[string]struct {
+		cookie string
+		value  string
+		rule   string
+	}{
+		"oneof":         {"theme", "blue", "oneof=light dark"},
+		"min":           {"page_size", "9", "min=10"},
+		"max":           {"page_size", "101", "max=100"},
+		"pattern":       {"locale", "english", "pattern=^[a-z]{2}(-[A-Z]{2})?$"},
+		"len":           {"code", "abc", "len=4"},
+		"required":      {"name", "", "required"},
+		"slice max":     {"tags", "a,b,c", "max=2"},
+		"slice element": {"tags", "a,d", "oneof=a b c"},
+		"pointer":       {"ratio", "0.25", "min=0.5"},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		for name, value := range valid {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		}
+
+		if err := unsignedManager.PopulateFromCookies(req, &MyStruct{}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range valid {
+				if name == tt.cookie {
+					value = tt.value
+				}
+				req.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+
+			err := unsignedManager.PopulateFromCookies(req, &MyStruct{})
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("Expected FieldError, but got '%v'", err)
+			}
+			if fieldErr.Cookie != tt.cookie {
+				t.Errorf("Expected cookie '%s', but got '%s'", tt.cookie, fieldErr.Cookie)
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, but got '%v'", err)
+			}
+			if validationErr.Rule != tt.rule {
+				t.Errorf("Expected rule '%s', but got '%s'", tt.rule, validationErr.Rule)
+			}
+		})
+	}
+}
+
+func TestPopulateFromCookies_ValidationUnsupported(t *testing.T) {
+	tests := map[string]interface{}{
+		"len on int": &struct {
+			Field int `cookie:"cookie,len=1"`
+		}{},
+		"min on bool": &struct {
+			Field bool `cookie:"cookie,min=1"`
+		}{},
+	}
+
+	for name, dest := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(&http.Cookie{Name: "cookie", Value: "1"})
+
+			err := unsignedManager.PopulateFromCookies(req, dest)
+
+			var unsupported *ErrUnsupportedType
+			if !errors.As(err, &unsupported) {
+				t.Errorf("Expected ErrUnsupportedType, but got '%v'", err)
+			}
+		})
+	}
+}
+
+func TestPopulateFromCookies_ValidationSkipsAbsentFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	type MyStruct struct {
+		Optional string `cookie:"optional,omitempty,min=3"`
+		Pointer  *int   `cookie:"pointer,min=3"`
+	}
+
+	if err := unsignedManager.PopulateFromCookies(req, &MyStruct{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPopulateFromCookies_RequiredAbsent(t *testing.T) {
+	tests := map[string]interface{}{
+		"value": &struct {
+			Field string `cookie:"cookie,required"`
+		}{},
+		"pointer": &struct {
+			Field *int `cookie:"cookie,required"`
+		}{},
+		"omitempty": &struct {
+			Field string `cookie:"cookie,omitempty,required"`
+		}{},
+		"signed": &struct {
+			Field string `cookie:"cookie,signed,omitempty,required"`
+		}{},
+	}
+
+	for name, dest := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := signedManager.PopulateFromCookies(httptest.NewRequest(http.MethodGet, "/", nil), dest)
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, but got '%v'", err)
+			}
+			if validationErr.Rule != "required" {
+				t.Errorf("Expected rule 'required', but got '%s'", validationErr.Rule)
+			}
+			if !errors.Is(err, http.ErrNoCookie) {
+				t.Errorf("Expected error to wrap '%v', but got '%v'", http.ErrNoCookie, err)
+			}
+		})
+	}
+}
+
+func TestPopulateFromCookies_RequiredAbsentWithDefault(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"cookie,required,default=value"`
+	}
+
+	dest := &MyStruct{}
+	if err := unsignedManager.PopulateFromCookies(httptest.NewRequest(http.MethodGet, "/", nil), dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if dest.Field != "value" {
+		t.Errorf("Expected value 'value', but got '%s'", dest.Field)
+	}
+}
+
+func TestWithValidator(t *testing.T) {
+	errNotPrefixed := errors.New("missing prefix")
+
+	manager := NewManager(WithValidator("prefix", func(value interface{}, param string) error {
+		if !strings.HasPrefix(value.(string), param) {
+			return errNotPrefixed
+		}
+		return nil
+	}))
+
+	type MyStruct struct {
+		ID string `cookie:"id,prefix=usr_"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "usr_123"})
+
+	if err := manager.PopulateFromCookies(req, &MyStruct{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "id", Value: "org_123"})
+
+	err := manager.PopulateFromCookies(req, &MyStruct{})
+	if !errors.Is(err, errNotPrefixed) {
+		t.Errorf("Expected error '%v', but got '%v'", errNotPrefixed, err)
+	}
+}
+
+func TestPopulateFromCookies_UnknownRule(t *testing.T) {
+	tests := map[string]struct {
+		dest     interface{}
+		expected string
+	}{
+		"misspelled rule": {
+			&struct {
+				Name string `cookie:"name,requird"`
+			}{},
+			`cookie: field Name: unknown tag option or rule "requird"`,
+		},
+		"misspelled option": {
+			&struct {
+				Token string `cookie:"token,singed"`
+			}{},
+			`cookie: field Token: unknown tag option or rule "singed"`,
+		},
+		"pattern with comma": {
+			&struct {
+				Lang string `cookie:"lang,pattern=^[a-z]{2,3}$"`
+			}{},
+			`cookie: field Lang: unknown tag option or rule "3}$"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			err := NewManager().PopulateFromCookies(req, tt.dest)
+
+			var ruleErr *UnknownRuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Expected UnknownRuleError, but got %v", err)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Expected error '%s', but got '%v'", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package cookie
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteToCookies writes the tagged fields of a struct as cookies. It is the
+// inverse of PopulateFromCookies, so anything written can be read back.
+func (m *Manager) WriteToCookies(w http.ResponseWriter, src interface{}, opts ...Options) error {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ErrNonNilPointerRequired
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return &ErrUnsupportedType{Type: v.Type()}
+	}
+	if !v.CanAddr() {
+		// Copy the struct so methods with pointer receivers, such as those of
+		// encoding.TextMarshaler, can be called on its fields.
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+
+	return m.walkFields(v, func(_ string, fieldVal reflect.Value, tagOpts tagOptions) error {
+		if tagOpts.omitempty && fieldVal.IsZero() {
+			return nil
+		}
+		if fieldVal.Kind() == reflect.Pointer && fieldVal.IsNil() {
+			return nil
+		}
+
+		var value string
+		var err error
+		if tagOpts.json {
+			value, err = encodeJSONValue(fieldVal.Interface())
+		} else {
+			value, err = m.formatFieldValue(fieldVal, tagOpts.separator())
+		}
+		if err != nil {
+			return err
+		}
+
+		fieldOpts := o
+		fieldOpts.Encrypted = tagOpts.encrypted
+		fieldOpts.Signed = tagOpts.signed && !tagOpts.unsigned
+
+		return m.Set(w, tagOpts.name, value, fieldOpts)
+	})
+}
+
+// formatFieldValue formats the value of a struct field based on its type, in
+// the form expected by setFieldValue. Slice elements and map entries are
+// separated by sep, and ErrAmbiguousValue is returned when they could not be
+// told apart when reading the value back.
+func (m *Manager) formatFieldValue(fieldVal reflect.Value, sep string) (string, error) {
+	// Nil pointers are checked first, as the methods of encoding.TextMarshaler
+	// may not handle nil receivers.
+	if fieldVal.Kind() == reflect.Pointer && fieldVal.IsNil() {
+		return "", nil
+	}
+
+	// time.Time implements encoding.TextMarshaler, but is formatted below so
+	// values keep being written as RFC 3339.
+	if fieldVal.Type() != reflect.TypeOf(time.Time{}) {
+		if value, ok, err := marshalFieldValue(fieldVal); ok {
+			return value, err
+		}
+	}
+
+	switch fieldVal.Kind() {
+	case reflect.Pointer:
+		return m.formatFieldValue(fieldVal.Elem(), sep)
+	case reflect.Bool:
+		return strconv.FormatBool(fieldVal.Bool()), nil
+	case reflect.String:
+		return fieldVal.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(fieldVal.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(fieldVal.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(fieldVal.Float(), 'f', -1, fieldVal.Type().Bits()), nil
+	case reflect.Slice:
+		strSlice := make([]string, fieldVal.Len())
+		for i := range strSlice {
+			str, err := m.formatFieldValue(fieldVal.Index(i), sep)
+			if err != nil {
+				return "", err
+			}
+			if strings.Contains(str, sep) {
+				return "", ErrAmbiguousValue
+			}
+			strSlice[i] = str
+		}
+		// An empty value is read back as an empty slice.
+		if len(strSlice) == 1 && strSlice[0] == "" {
+			return "", ErrAmbiguousValue
+		}
+		return strings.Join(strSlice, sep), nil
+	case reflect.Map:
+		entries := make([]string, 0, fieldVal.Len())
+		iter := fieldVal.MapRange()
+		for iter.Next() {
+			k, err := m.formatFieldValue(iter.Key(), sep)
+			if err != nil {
+				return "", err
+			}
+			v, err := m.formatFieldValue(iter.Value(), sep)
+			if err != nil {
+				return "", err
+			}
+			if strings.Contains(k, sep) || strings.Contains(k, "=") || strings.Contains(v, sep) {
+				return "", ErrAmbiguousValue
+			}
+			entries = append(entries, k+"="+v)
+		}
+		sort.Strings(entries)
+		return strings.Join(entries, sep), nil
+	case reflect.Struct:
+		if fieldVal.Type() == reflect.TypeOf(time.Time{}) {
+			return fieldVal.Interface().(time.Time).Format(time.RFC3339), nil
+		}
+	}
+	return "", &ErrUnsupportedType{Type: fieldVal.Type()}
+}
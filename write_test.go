@@ -0,0 +1,469 @@
+package cookie
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestManager_WriteToCookies(t *testing.T) {
+	type MyStruct struct {
+		UntaggedField string
+		Default       string    `cookie:"cookie1"`
+		Unsigned      string    `cookie:"cookie2,unsigned"`
+		Signed        string    `cookie:"cookie3,signed"`
+		Boolean       bool      `cookie:"cookie4"`
+		Integer       int       `cookie:"cookie5"`
+		UInteger      uint      `cookie:"cookie6"`
+		Float         float64   `cookie:"cookie7"`
+		StringSlice   []string  `cookie:"cookie8"`
+		IntSlice      []int     `cookie:"cookie9"`
+		Timestamp     time.Time `cookie:"cookie10"`
+		NotSet        string    `cookie:"cookie11,omitempty"`
+	}
+
+	src := &MyStruct{
+		UntaggedField: "untagged",
+		Default:       "test",
+		Unsigned:      "test",
+		Signed:        "test",
+		Boolean:       true,
+		Integer:       -123,
+		UInteger:      123,
+		Float:         123.45,
+		StringSlice:   []string{"a", "b", "c"},
+		IntSlice:      []int{1, 2, 3},
+		Timestamp:     time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+
+	w := httptest.NewRecorder()
+	err := signedManager.WriteToCookies(w, src, Options{HttpOnly: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedValues := map[string]string{
+		"cookie1":  "test",
+		"cookie2":  "test",
+		"cookie3":  signCookieValue("test", signedManager.signingKey),
+		"cookie4":  "true",
+		"cookie5":  "-123",
+		"cookie6":  "123",
+		"cookie7":  "123.45",
+		"cookie8":  "a,b,c",
+		"cookie9":  "1,2,3",
+		"cookie10": "2021-01-02T15:04:05Z",
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != len(expectedValues) {
+		t.Fatalf("Expected %d cookies, but got %d", len(expectedValues), len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		if cookie.Value != expectedValues[cookie.Name] {
+			t.Errorf("Expected cookie '%s' value '%s', but got '%s'", cookie.Name, expectedValues[cookie.Name], cookie.Value)
+		}
+		if !cookie.HttpOnly {
+			t.Errorf("Expected cookie '%s' to be HttpOnly", cookie.Name)
+		}
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	err = signedManager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	src.UntaggedField = ""
+	if !reflect.DeepEqual(dest, src) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_Encrypted(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"cookie,encrypted"`
+	}
+
+	w := httptest.NewRecorder()
+	err := encryptedManager.WriteToCookies(w, MyStruct{Field: "secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+
+	dest := &MyStruct{}
+	err = encryptedManager.PopulateFromCookies(req, dest)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if dest.Field != "secret" {
+		t.Errorf("Expected value '%s', but got '%s'", "secret", dest.Field)
+	}
+}
+
+func TestManager_WriteToCookies_EncryptedOption(t *testing.T) {
+	type MyStruct struct {
+		Plain     string `cookie:"plain"`
+		Signed    string `cookie:"signed,signed"`
+		Encrypted string `cookie:"encrypted,encrypted"`
+	}
+
+	m := NewManager(
+		WithSigningKey([]byte("super-secret-key")),
+		WithEncryptionKey([]byte("0123456789abcdef0123456789abcdef")),
+	)
+	src := MyStruct{Plain: "plain", Signed: "signed", Encrypted: "encrypted"}
+
+	w := httptest.NewRecorder()
+	if err := m.WriteToCookies(w, src, Options{Encrypted: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "plain" && cookie.Value != "plain" {
+			t.Errorf("Expected cookie 'plain' not to be encrypted, but got '%s'", cookie.Value)
+		}
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := m.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if *dest != src {
+		t.Errorf("Unexpected result. Got: %+v, want: %+v", *dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_EncryptedNoKey(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"cookie,encrypted"`
+	}
+
+	w := httptest.NewRecorder()
+	err := unsignedManager.WriteToCookies(w, MyStruct{Field: "secret"})
+	if err != ErrEncryptionKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrEncryptionKeyRequired, err)
+	}
+}
+
+func TestManager_WriteToCookies_SignedNoKey(t *testing.T) {
+	type MyStruct struct {
+		Field string `cookie:"cookie,signed"`
+	}
+
+	w := httptest.NewRecorder()
+	err := unsignedManager.WriteToCookies(w, MyStruct{Field: "value"})
+	if err != ErrSigningKeyRequired {
+		t.Errorf("Expected error '%v', but got '%v'", ErrSigningKeyRequired, err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be written")
+	}
+}
+
+func TestManager_WriteToCookies_NonNilPointerRequired(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	var src *struct{}
+	err := unsignedManager.WriteToCookies(w, src)
+	if err != ErrNonNilPointerRequired {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestManager_WriteToCookies_NotStruct(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := unsignedManager.WriteToCookies(w, "invalid")
+	if err == nil {
+		t.Error("Expected error, but got nil")
+	}
+
+	expectedError := "cookie: unsupported type: string"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
+	}
+}
+
+func TestManager_WriteToCookies_ErrUnsupportedType(t *testing.T) {
+	tests := map[string]struct {
+		src          interface{}
+		expectedType string
+	}{
+		"complex128": {
+			src: struct {
+				Field complex128 `cookie:"cookie"`
+			}{},
+			expectedType: "complex128",
+		},
+		"slice": {
+			src: struct {
+				Field []complex128 `cookie:"cookie"`
+			}{Field: []complex128{1}},
+			expectedType: "complex128",
+		},
+		"map": {
+			src: struct {
+				Field map[string]complex128 `cookie:"cookie"`
+			}{Field: map[string]complex128{"a": 1}},
+			expectedType: "complex128",
+		},
+		"map key": {
+			src: struct {
+				Field map[complex128]string `cookie:"cookie"`
+			}{Field: map[complex128]string{1: "a"}},
+			expectedType: "complex128",
+		},
+		"struct": {
+			src: struct {
+				Field struct{} `cookie:"cookie"`
+			}{},
+			expectedType: "struct {}",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := unsignedManager.WriteToCookies(w, tt.src)
+			if err == nil {
+				t.Fatal("Expected error, but got nil")
+			}
+
+			expectedError := "cookie: unsupported type: " + tt.expectedType
+			if err.Error() != expectedError {
+				t.Errorf("Expected error '%s', but got '%v'", expectedError, err)
+			}
+		})
+	}
+}
+
+func TestManager_WriteToCookies_NestedStructs(t *testing.T) {
+	type Base struct {
+		UserID int `cookie:"User-ID"`
+	}
+
+	type Prefs struct {
+		Theme string `cookie:"theme"`
+		Lang  string `cookie:"lang,omitempty"`
+	}
+
+	type MyStruct struct {
+		Base
+		Prefs  Prefs `cookie:"prefs_,inline"`
+		Signed Prefs `cookie:"signed_,inline,signed"`
+	}
+
+	src := MyStruct{
+		Base:   Base{UserID: 123},
+		Prefs:  Prefs{Theme: "dark"},
+		Signed: Prefs{Theme: "light", Lang: "en"},
+	}
+
+	w := httptest.NewRecorder()
+	err := signedManager.WriteToCookies(w, src)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedValues := map[string]string{
+		"User-ID":      "123",
+		"prefs_theme":  "dark",
+		"signed_theme": signCookieValue("light", signedManager.signingKey),
+		"signed_lang":  signCookieValue("en", signedManager.signingKey),
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != len(expectedValues) {
+		t.Fatalf("Expected %d cookies, but got %d", len(expectedValues), len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		if cookie.Value != expectedValues[cookie.Name] {
+			t.Errorf("Expected cookie '%s' value '%s', but got '%s'", cookie.Name, expectedValues[cookie.Name], cookie.Value)
+		}
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := signedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*dest, src) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", *dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_Pointers(t *testing.T) {
+	type MyStruct struct {
+		Int    *int    `cookie:"int"`
+		String *string `cookie:"string,omitempty"`
+		Absent *int    `cookie:"absent"`
+	}
+
+	zero, empty := 0, ""
+	src := MyStruct{Int: &zero, String: &empty}
+
+	w := httptest.NewRecorder()
+	err := unsignedManager.WriteToCookies(w, src)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 cookies, but got %d", len(cookies))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := unsignedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*dest, src) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", *dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_SlicesAndMaps(t *testing.T) {
+	type MyStruct struct {
+		Floats []float64         `cookie:"floats"`
+		Uints  []uint8           `cookie:"uints,sep=|"`
+		Times  []time.Time       `cookie:"times"`
+		StrMap map[string]string `cookie:"strmap"`
+		IntMap map[string]int    `cookie:"intmap,sep=|"`
+	}
+
+	src := MyStruct{
+		Floats: []float64{1.5, 2.5},
+		Uints:  []uint8{1, 2, 3},
+		Times: []time.Time{
+			time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC),
+			time.Date(2022, 1, 2, 15, 4, 5, 0, time.UTC),
+		},
+		StrMap: map[string]string{"theme": "dark", "lang": "en"},
+		IntMap: map[string]int{"b": 2, "a": 1},
+	}
+
+	w := httptest.NewRecorder()
+	err := unsignedManager.WriteToCookies(w, src)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedValues := map[string]string{
+		"floats": "1.5,2.5",
+		"uints":  "1|2|3",
+		"times":  "2021-01-02T15:04:05Z,2022-01-02T15:04:05Z",
+		"strmap": "lang=en,theme=dark",
+		"intmap": "a=1|b=2",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Value != expectedValues[cookie.Name] {
+			t.Errorf("Expected cookie '%s' value '%s', but got '%s'", cookie.Name, expectedValues[cookie.Name], cookie.Value)
+		}
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := unsignedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*dest, src) {
+		t.Errorf("Unexpected result. Got: %v, want: %v", *dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_EmptySlicesAndMaps(t *testing.T) {
+	type MyStruct struct {
+		Ints    []int             `cookie:"ints"`
+		Strings []string          `cookie:"strings"`
+		NilInts []int             `cookie:"nil_ints"`
+		Map     map[string]string `cookie:"map"`
+		Equals  map[string]string `cookie:"equals"`
+		Empty   []string          `cookie:"empty"`
+	}
+
+	src := MyStruct{
+		Ints:    []int{},
+		Strings: []string{},
+		Map:     map[string]string{},
+		Equals:  map[string]string{"query": "a=b"},
+		Empty:   []string{"", ""},
+	}
+
+	w := httptest.NewRecorder()
+	if err := unsignedManager.WriteToCookies(w, src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+
+	dest := &MyStruct{}
+	if err := unsignedManager.PopulateFromCookies(req, dest); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Nil slices are read back as empty slices.
+	src.NilInts = []int{}
+	if !reflect.DeepEqual(*dest, src) {
+		t.Errorf("Unexpected result. Got: %#v, want: %#v", *dest, src)
+	}
+}
+
+func TestManager_WriteToCookies_AmbiguousValue(t *testing.T) {
+	tests := map[string]interface{}{
+		"element with separator": struct {
+			Field []string `cookie:"field"`
+		}{[]string{"a,b", "c"}},
+		"element with custom separator": struct {
+			Field []string `cookie:"field,sep=|"`
+		}{[]string{"a|b"}},
+		"single empty element": struct {
+			Field []string `cookie:"field"`
+		}{[]string{""}},
+		"map key with equals": struct {
+			Field map[string]string `cookie:"field"`
+		}{map[string]string{"a=b": "c"}},
+		"map key with separator": struct {
+			Field map[string]string `cookie:"field"`
+		}{map[string]string{"a,b": "c"}},
+		"map value with separator": struct {
+			Field map[string]string `cookie:"field"`
+		}{map[string]string{"a": "b,c"}},
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := unsignedManager.WriteToCookies(w, src)
+			if err != ErrAmbiguousValue {
+				t.Errorf("Expected error '%v', but got '%v'", ErrAmbiguousValue, err)
+			}
+			if len(w.Result().Cookies()) != 0 {
+				t.Errorf("Expected no cookies, but got %d", len(w.Result().Cookies()))
+			}
+		})
+	}
+}